@@ -129,7 +129,7 @@
 {{- range $_ := (list 1) -}}
 {{- $values := $dot.Values.AsMap -}}
 {{- $externalKafkaListenerName := (get (fromJson (include "redpanda.getFirstExternalKafkaListener" (dict "a" (list $dot) ))) "r") -}}
-{{- $listener := (index $values.listeners.kafka.external $externalKafkaListenerName) -}}
+{{- $listener := (deepCopy (index $values.listeners.kafka.external $externalKafkaListenerName)) -}}
 {{- $port := (($values.listeners.kafka.port | int) | int) -}}
 {{- if (gt (($listener.port | int) | int) ((1 | int) | int)) -}}
 {{- $port = (($listener.port | int) | int) -}}
@@ -153,7 +153,7 @@
 {{- $keys := (keys $values.listeners.admin.external) -}}
 {{- $_ := (sortAlpha $keys) -}}
 {{- $externalAdminListenerName := (first $keys) -}}
-{{- $listener := (index $values.listeners.admin.external (get (fromJson (include "_shims.typeassertion" (dict "a" (list "string" $externalAdminListenerName) ))) "r")) -}}
+{{- $listener := (deepCopy (index $values.listeners.admin.external (get (fromJson (include "_shims.typeassertion" (dict "a" (list "string" $externalAdminListenerName) ))) "r"))) -}}
 {{- $port := (($values.listeners.admin.port | int) | int) -}}
 {{- if (gt (($listener.port | int) | int) (1 | int)) -}}
 {{- $port = (($listener.port | int) | int) -}}
@@ -212,7 +212,7 @@
 {{- $values := $dot.Values.AsMap -}}
 {{- $brokerList := (list ) -}}
 {{- $r := ($values.statefulset.replicas | int) -}}
-{{- range $_, $i := untilStep ((0 | int)|int) (($values.statefulset.replicas | int)|int) (1|int) -}}
+{{- range $_, $i := untilStep ((0 | int)|int) ($r|int) (1|int) -}}
 {{- $brokerList = (concat (default (list ) $brokerList) (list (printf "%s-%d.%s:%d" (get (fromJson (include "redpanda.Fullname" (dict "a" (list $dot) ))) "r") $i (get (fromJson (include "redpanda.InternalDomain" (dict "a" (list $dot) ))) "r") (($values.listeners.kafka.port | int) | int)))) -}}
 {{- end -}}
 {{- $adminTLS := (coalesce nil) -}}
@@ -285,7 +285,7 @@
 {{- range $_, $i := untilStep ((0 | int)|int) (($values.statefulset.replicas | int)|int) (1|int) -}}
 {{- $brokerList = (concat (default (list ) $brokerList) (list (dict "address" (printf "%s-%d.%s" (get (fromJson (include "redpanda.Fullname" (dict "a" (list $dot) ))) "r") $i (get (fromJson (include "redpanda.InternalDomain" (dict "a" (list $dot) ))) "r")) "port" ($values.listeners.kafka.port | int) ))) -}}
 {{- end -}}
-{{- $kafkaTLS := $values.listeners.kafka.tls -}}
+{{- $kafkaTLS := (deepCopy $values.listeners.kafka.tls) -}}
 {{- $brokerTLS := (coalesce nil) -}}
 {{- if (get (fromJson (include "redpanda.InternalTLS.IsEnabled" (dict "a" (list $values.listeners.kafka.tls $values.tls) ))) "r") -}}
 {{- $brokerTLS = (dict "enabled" true "cert_file" (printf "/etc/tls/certs/%s/tls.crt" $kafkaTLS.cert) "key_file" (printf "/etc/tls/certs/%s/tls.key" $kafkaTLS.cert) "require_client_auth" $kafkaTLS.requireClientAuth "truststore_file" (get (fromJson (include "redpanda.InternalTLS.TrustStoreFilePath" (dict "a" (list $kafkaTLS $values.tls) ))) "r") ) -}}
@@ -360,7 +360,7 @@
 {{- $dot := (index .a 0) -}}
 {{- range $_ := (list 1) -}}
 {{- $values := $dot.Values.AsMap -}}
-{{- $r := $values.listeners.rpc -}}
+{{- $r := (deepCopy $values.listeners.rpc) -}}
 {{- if (and (not ((or (or (get (fromJson (include "redpanda.RedpandaAtLeast_22_2_atleast_22_2_10" (dict "a" (list $dot) ))) "r") (get (fromJson (include "redpanda.RedpandaAtLeast_22_3_atleast_22_3_13" (dict "a" (list $dot) ))) "r")) (get (fromJson (include "redpanda.RedpandaAtLeast_23_1_2" (dict "a" (list $dot) ))) "r")))) ((or (and (eq $r.tls.enabled (coalesce nil)) $values.tls.enabled) (get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $r.tls.enabled false) ))) "r")))) -}}
 {{- $_ := (fail (printf "Redpanda version v%s does not support TLS on the RPC port. Please upgrade. See technical service bulletin 2023-01." (trimPrefix "v" (get (fromJson (include "redpanda.Tag" (dict "a" (list $dot) ))) "r")))) -}}
 {{- end -}}
@@ -410,7 +410,7 @@
 {{- range $_ := (list 1) -}}
 {{- $values := $dot.Values.AsMap -}}
 {{- $chartFlags := (dict "smp" (printf "%d" ($smp | int)) "memory" (printf "%dM" (((get (fromJson (include "redpanda.RedpandaMemory" (dict "a" (list $dot) ))) "r") | int64) | int)) "reserve-memory" (printf "%dM" (((get (fromJson (include "redpanda.RedpandaReserveMemory" (dict "a" (list $dot) ))) "r") | int64) | int)) "default-log-level" $values.logging.logLevel ) -}}
-{{- if (eq (index $values.config.node "developer_mode") true) -}}
+{{- if (eq (toJson (index $values.config.node "developer_mode")) (toJson true)) -}}
 {{- $_ := (unset $chartFlags "reserve-memory") -}}
 {{- end -}}
 {{- range $flag, $_ := $chartFlags -}}
@@ -125,10 +125,12 @@
 {{- break -}}
 {{- end -}}
 {{- $listeners := (list "kafka" "admin" "schemaRegistry" "rpc" "http") -}}
+{{- $tmp_returned_1 := false -}}
 {{- range $_, $listener := $listeners -}}
 {{- $tlsCert := (dig "listeners" $listener "tls" "cert" false $dot.Values.AsMap) -}}
 {{- $tlsEnabled := (dig "listeners" $listener "tls" "enabled" false $dot.Values.AsMap) -}}
 {{- if (and (not (empty $tlsEnabled)) (not (empty $tlsCert))) -}}
+{{- $tmp_returned_1 = true -}}
 {{- (dict "r" true) | toJson -}}
 {{- break -}}
 {{- end -}}
@@ -142,10 +144,17 @@
 {{- $tlsCert := (dig "listeners" $listener "external" $key "tls" "cert" false $dot.Values.AsMap) -}}
 {{- $tlsEnabled := (dig "listeners" $listener "external" $key "tls" "enabled" false $dot.Values.AsMap) -}}
 {{- if (and (and (not (empty $enabled)) (not (empty $tlsCert))) (not (empty $tlsEnabled))) -}}
+{{- $tmp_returned_1 = true -}}
 {{- (dict "r" true) | toJson -}}
 {{- break -}}
 {{- end -}}
 {{- end -}}
+{{- if $tmp_returned_1 -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+{{- if $tmp_returned_1 -}}
+{{- break -}}
 {{- end -}}
 {{- (dict "r" false) | toJson -}}
 {{- break -}}
@@ -156,13 +165,18 @@
 {{- $dot := (index .a 0) -}}
 {{- range $_ := (list 1) -}}
 {{- $listeners := (list "kafka" "admin" "schemaRegistry" "rpc" "http") -}}
+{{- $tmp_returned_2 := false -}}
 {{- range $_, $listener := $listeners -}}
 {{- $required := (dig $listener "tls" "requireClientAuth" false $dot.Values.AsMap) -}}
 {{- if (not (empty $required)) -}}
+{{- $tmp_returned_2 = true -}}
 {{- (dict "r" true) | toJson -}}
 {{- break -}}
 {{- end -}}
 {{- end -}}
+{{- if $tmp_returned_2 -}}
+{{- break -}}
+{{- end -}}
 {{- (dict "r" false) | toJson -}}
 {{- break -}}
 {{- end -}}
@@ -217,7 +231,7 @@
 {{- $certNames := (keys $values.tls.certs) -}}
 {{- $_ := (sortAlpha $certNames) -}}
 {{- range $_, $name := $certNames -}}
-{{- $cert := (index $values.tls.certs $name) -}}
+{{- $cert := (deepCopy (index $values.tls.certs $name)) -}}
 {{- $volumes = (concat (default (list ) $volumes) (list (mustMergeOverwrite (dict "name" "" ) (mustMergeOverwrite (dict ) (dict "secret" (mustMergeOverwrite (dict ) (dict "secretName" (get (fromJson (include "redpanda.CertSecretName" (dict "a" (list $dot $name $cert) ))) "r") "defaultMode" (0o440 | int) )) )) (dict "name" (printf "redpanda-%s-cert" $name) )))) -}}
 {{- end -}}
 {{- if (get (fromJson (include "redpanda.ClientAuthRequired" (dict "a" (list $dot) ))) "r") -}}
@@ -25,11 +25,11 @@
 {{- $azureStorageAccountExists := $tmp_tuple_2.T2 -}}
 {{- $asa := $tmp_tuple_2.T1 -}}
 {{- if (and (and (and $azureContainerExists (ne $ac (coalesce nil))) $azureStorageAccountExists) (ne $asa (coalesce nil))) -}}
-{{- $envars = (concat (default (list ) $envars) (default (list ) (get (fromJson (include "redpanda.addAzureSharedKey" (dict "a" (list $tieredStorageConfig $values) ))) "r"))) -}}
+{{- $envars = (concat (default (list ) $envars) (default (list ) (get (fromJson (include "redpanda.addAzureSharedKey" (dict "a" (list $tieredStorageConfig (deepCopy $values)) ))) "r"))) -}}
 {{- else -}}
-{{- $envars = (concat (default (list ) $envars) (default (list ) (get (fromJson (include "redpanda.addCloudStorageSecretKey" (dict "a" (list $tieredStorageConfig $values) ))) "r"))) -}}
+{{- $envars = (concat (default (list ) $envars) (default (list ) (get (fromJson (include "redpanda.addCloudStorageSecretKey" (dict "a" (list $tieredStorageConfig (deepCopy $values)) ))) "r"))) -}}
 {{- end -}}
-{{- $envars = (concat (default (list ) $envars) (default (list ) (get (fromJson (include "redpanda.addCloudStorageAccessKey" (dict "a" (list $tieredStorageConfig $values) ))) "r"))) -}}
+{{- $envars = (concat (default (list ) $envars) (default (list ) (get (fromJson (include "redpanda.addCloudStorageAccessKey" (dict "a" (list $tieredStorageConfig (deepCopy $values)) ))) "r"))) -}}
 {{- range $k, $v := $tieredStorageConfig -}}
 {{- if (or (or (eq $k "cloud_storage_access_key") (eq $k "cloud_storage_secret_key")) (eq $k "cloud_storage_azure_shared_key")) -}}
 {{- continue -}}
@@ -63,7 +63,7 @@
 {{- $ok_6 := $tmp_tuple_4.T2 -}}
 {{- $v_5 := $tmp_tuple_4.T1 -}}
 {{- $ak_7 := $values.storage.tiered.credentialsSecretRef.accessKey -}}
-{{- if (and $ok_6 (ne $v_5 "")) -}}
+{{- if (and $ok_6 (ne (toJson $v_5) (toJson ""))) -}}
 {{- (dict "r" (list (mustMergeOverwrite (dict "name" "" ) (dict "name" "RPK_CLOUD_STORAGE_ACCESS_KEY" "value" (get (fromJson (include "_shims.typeassertion" (dict "a" (list "string" $v_5) ))) "r") )))) | toJson -}}
 {{- break -}}
 {{- else -}}{{- if (get (fromJson (include "redpanda.SecretRef.IsValid" (dict "a" (list $ak_7) ))) "r") -}}
@@ -84,7 +84,7 @@
 {{- $ok_9 := $tmp_tuple_5.T2 -}}
 {{- $v_8 := $tmp_tuple_5.T1 -}}
 {{- $sk_10 := $values.storage.tiered.credentialsSecretRef.secretKey -}}
-{{- if (and $ok_9 (ne $v_8 "")) -}}
+{{- if (and $ok_9 (ne (toJson $v_8) (toJson ""))) -}}
 {{- (dict "r" (list (mustMergeOverwrite (dict "name" "" ) (dict "name" "RPK_CLOUD_STORAGE_SECRET_KEY" "value" (get (fromJson (include "_shims.typeassertion" (dict "a" (list "string" $v_8) ))) "r") )))) | toJson -}}
 {{- break -}}
 {{- else -}}{{- if (get (fromJson (include "redpanda.SecretRef.IsValid" (dict "a" (list $sk_10) ))) "r") -}}
@@ -105,7 +105,7 @@
 {{- $ok_12 := $tmp_tuple_6.T2 -}}
 {{- $v_11 := $tmp_tuple_6.T1 -}}
 {{- $sk_13 := $values.storage.tiered.credentialsSecretRef.secretKey -}}
-{{- if (and $ok_12 (ne $v_11 "")) -}}
+{{- if (and $ok_12 (ne (toJson $v_11) (toJson ""))) -}}
 {{- (dict "r" (list (mustMergeOverwrite (dict "name" "" ) (dict "name" "RPK_CLOUD_STORAGE_AZURE_SHARED_KEY" "value" (get (fromJson (include "_shims.typeassertion" (dict "a" (list "string" $v_11) ))) "r") )))) | toJson -}}
 {{- break -}}
 {{- else -}}{{- if (get (fromJson (include "redpanda.SecretRef.IsValid" (dict "a" (list $sk_13) ))) "r") -}}
@@ -6,6 +6,7 @@
 {{- $values := $dot.Values.AsMap -}}
 {{- $userEnv := (coalesce nil) -}}
 {{- range $_, $container := $values.statefulset.podTemplate.spec.containers -}}
+{{- $container = (deepCopy $container) -}}
 {{- if (eq $container.name "redpanda") -}}
 {{- $userEnv = $container.env -}}
 {{- end -}}
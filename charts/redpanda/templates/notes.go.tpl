@@ -41,7 +41,7 @@
 {{- $_ := (sortAlpha $profiles) -}}
 {{- $profileName := (index $profiles (0 | int)) -}}
 {{- $notes = (concat (default (list ) $notes) (list `` `Set up rpk for access to your external listeners:`)) -}}
-{{- $profile := (index $values.listeners.kafka.external $profileName) -}}
+{{- $profile := (deepCopy (index $values.listeners.kafka.external $profileName)) -}}
 {{- if (get (fromJson (include "redpanda.TLSEnabled" (dict "a" (list $dot) ))) "r") -}}
 {{- $external := "" -}}
 {{- if (and (ne $profile.tls (coalesce nil)) (ne $profile.tls.cert (coalesce nil))) -}}
@@ -40,6 +40,7 @@
 {{- $values := $dot.Values.AsMap -}}
 {{- $result := (coalesce nil) -}}
 {{- range $_, $t := $values.tolerations -}}
+{{- $t = (deepCopy $t) -}}
 {{- $result = (concat (default (list ) $result) (list (merge (dict ) $t))) -}}
 {{- end -}}
 {{- (dict "r" $result) | toJson -}}
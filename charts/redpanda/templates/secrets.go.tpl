@@ -187,6 +187,7 @@ echo "passed"`) -}}
 {{- if (gt ((get (fromJson (include "_shims.len" (dict "a" (list $values.listeners.kafka.external) ))) "r") | int) (0 | int)) -}}
 {{- $externalCounter := (0 | int) -}}
 {{- range $externalName, $externalVals := $values.listeners.kafka.external -}}
+{{- $externalVals = (deepCopy $externalVals) -}}
 {{- $externalCounter = ((add $externalCounter (1 | int)) | int) -}}
 {{- $snippet = (concat (default (list ) $snippet) (list `` (printf `ADVERTISED_%s_ADDRESSES=()` (upper $listenerName)))) -}}
 {{- range $_, $replicaIndex := (until ($values.statefulset.replicas | int)) -}}
@@ -227,6 +228,7 @@ echo "passed"`) -}}
 {{- if (gt ((get (fromJson (include "_shims.len" (dict "a" (list $values.listeners.http.external) ))) "r") | int) (0 | int)) -}}
 {{- $externalCounter := (0 | int) -}}
 {{- range $externalName, $externalVals := $values.listeners.http.external -}}
+{{- $externalVals = (deepCopy $externalVals) -}}
 {{- $externalCounter = ((add $externalCounter (1 | int)) | int) -}}
 {{- $snippet = (concat (default (list ) $snippet) (list `` (printf `ADVERTISED_%s_ADDRESSES=()` (upper $listenerName)))) -}}
 {{- range $_, $replicaIndex := (until ($values.statefulset.replicas | int)) -}}
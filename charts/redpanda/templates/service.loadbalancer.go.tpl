@@ -18,7 +18,7 @@
 {{- $selector := (get (fromJson (include "redpanda.StatefulSetPodLabelsSelector" (dict "a" (list $dot) ))) "r") -}}
 {{- $services := (coalesce nil) -}}
 {{- $replicas := ($values.statefulset.replicas | int) -}}
-{{- range $_, $i := untilStep ((0 | int)|int) (($values.statefulset.replicas | int)|int) (1|int) -}}
+{{- range $_, $i := untilStep ((0 | int)|int) ($replicas|int) (1|int) -}}
 {{- $podname := (printf "%s-%d" (get (fromJson (include "redpanda.Fullname" (dict "a" (list $dot) ))) "r") $i) -}}
 {{- $annotations := (dict ) -}}
 {{- range $k, $v := $values.external.annotations -}}
@@ -39,6 +39,7 @@
 {{- $_ := (set $podSelector "statefulset.kubernetes.io/pod-name" $podname) -}}
 {{- $ports := (coalesce nil) -}}
 {{- range $name, $listener := $values.listeners.admin.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.enabled $values.external.enabled) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -46,6 +47,7 @@
 {{- $ports = (concat (default (list ) $ports) (list (mustMergeOverwrite (dict "port" 0 "targetPort" 0 ) (dict "name" (printf "admin-%s" $name) "protocol" "TCP" "targetPort" ($listener.port | int) "port" ((get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.nodePort (index $fallbackPorts (0 | int))) ))) "r") | int) )))) -}}
 {{- end -}}
 {{- range $name, $listener := $values.listeners.kafka.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.enabled $values.external.enabled) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -53,6 +55,7 @@
 {{- $ports = (concat (default (list ) $ports) (list (mustMergeOverwrite (dict "port" 0 "targetPort" 0 ) (dict "name" (printf "kafka-%s" $name) "protocol" "TCP" "targetPort" ($listener.port | int) "port" ((get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.nodePort (index $fallbackPorts (0 | int))) ))) "r") | int) )))) -}}
 {{- end -}}
 {{- range $name, $listener := $values.listeners.http.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.enabled $values.external.enabled) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -60,6 +63,7 @@
 {{- $ports = (concat (default (list ) $ports) (list (mustMergeOverwrite (dict "port" 0 "targetPort" 0 ) (dict "name" (printf "http-%s" $name) "protocol" "TCP" "targetPort" ($listener.port | int) "port" ((get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.nodePort (index $fallbackPorts (0 | int))) ))) "r") | int) )))) -}}
 {{- end -}}
 {{- range $name, $listener := $values.listeners.schemaRegistry.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "_shims.ptr_Deref" (dict "a" (list $listener.enabled $values.external.enabled) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -14,6 +14,7 @@
 {{- end -}}
 {{- $ports := (coalesce nil) -}}
 {{- range $name, $listener := $values.listeners.admin.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "redpanda.AdminExternal.IsEnabled" (dict "a" (list $listener) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -24,6 +25,7 @@
 {{- $ports = (concat (default (list ) $ports) (list (mustMergeOverwrite (dict "port" 0 "targetPort" 0 ) (dict "name" (printf "admin-%s" $name) "protocol" "TCP" "port" ($listener.port | int) "nodePort" $nodePort )))) -}}
 {{- end -}}
 {{- range $name, $listener := $values.listeners.kafka.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "redpanda.KafkaExternal.IsEnabled" (dict "a" (list $listener) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -34,6 +36,7 @@
 {{- $ports = (concat (default (list ) $ports) (list (mustMergeOverwrite (dict "port" 0 "targetPort" 0 ) (dict "name" (printf "kafka-%s" $name) "protocol" "TCP" "port" ($listener.port | int) "nodePort" $nodePort )))) -}}
 {{- end -}}
 {{- range $name, $listener := $values.listeners.http.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "redpanda.HTTPExternal.IsEnabled" (dict "a" (list $listener) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -44,6 +47,7 @@
 {{- $ports = (concat (default (list ) $ports) (list (mustMergeOverwrite (dict "port" 0 "targetPort" 0 ) (dict "name" (printf "http-%s" $name) "protocol" "TCP" "port" ($listener.port | int) "nodePort" $nodePort )))) -}}
 {{- end -}}
 {{- range $name, $listener := $values.listeners.schemaRegistry.external -}}
+{{- $listener = (deepCopy $listener) -}}
 {{- if (not (get (fromJson (include "redpanda.SchemaRegistryExternal.IsEnabled" (dict "a" (list $listener) ))) "r")) -}}
 {{- continue -}}
 {{- end -}}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
 
 	"github.com/redpanda-data/helm-charts/pkg/gotohelm"
 	"golang.org/x/tools/go/packages"
@@ -12,6 +15,8 @@ import (
 
 func main() {
 	out := flag.String("write", "-", "The directory to write the transpiled templates to or - to write them to standard out")
+	sourceMaps := flag.String("source-maps", "", "If set, the directory to write source maps of the transpiled templates to")
+	annotateFailures := flag.Bool("annotate-failures", false, "If set, include the go function and file:line of the caller in the messages of fail and required")
 
 	flag.Parse()
 
@@ -24,10 +29,19 @@ func main() {
 		panic(err)
 	}
 
+	failed := false
 	for _, pkg := range pkgs {
-		chart, err := gotohelm.Transpile(pkg)
+		chart, err := gotohelm.TranspileWithOptions(pkg, gotohelm.TranspileOptions{
+			Diagnose:         true,
+			AnnotateFailures: *annotateFailures,
+		})
 		if err != nil {
-			fmt.Printf("Failed to transpile %q: %s\n", pkg.Name, err)
+			failed = true
+			if diagnostics, ok := err.(gotohelm.Diagnostics); ok {
+				printDiagnostics(cwd, diagnostics)
+			} else {
+				fmt.Fprintf(os.Stderr, "Failed to transpile %q: %s\n", pkg.Name, err)
+			}
 			continue
 		}
 
@@ -39,6 +53,31 @@ func main() {
 			}
 		}
 
+		if *sourceMaps != "" {
+			if err := writeSourceMaps(chart, *sourceMaps, cwd); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printDiagnostics prints diagnostics to stderr in the conventional
+// file:line:col format, relative to cwd where possible.
+func printDiagnostics(cwd string, diagnostics gotohelm.Diagnostics) {
+	for _, d := range diagnostics {
+		pos := d.Position()
+		if rel, err := filepath.Rel(cwd, pos.Filename); err == nil {
+			pos.Filename = rel
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %s (%s)\n", pos, d.Msg, d.Kind())
+		if d.Alternative != "" {
+			fmt.Fprintf(os.Stderr, "\tconsider using %s instead\n", d.Alternative)
+		}
 	}
 }
 
@@ -65,3 +104,29 @@ func writeToDir(chart *gotohelm.Chart, dir string) error {
 	}
 	return nil
 }
+
+// writeSourceMaps writes a JSON [gotohelm.SourceMap] of each file in chart to
+// dir as <name>.map.json. Source paths are made relative to cwd where
+// possible. They must not be written into the chart's templates directory as
+// helm would attempt to render them.
+func writeSourceMaps(chart *gotohelm.Chart, dir, cwd string) error {
+	for _, f := range chart.Files {
+		sm := f.WriteWithSourceMap(io.Discard)
+
+		for i, m := range sm.Mappings {
+			if rel, err := filepath.Rel(cwd, m.SourceFile); err == nil {
+				sm.Mappings[i].SourceFile = rel
+			}
+		}
+
+		out, err := json.MarshalIndent(sm, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path.Join(dir, f.Name+".map.json"), out, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+// gotohelmvet reports go code that can not be transpiled by gotohelm.
+//
+// It may be run directly or as a vet tool:
+//
+//	go vet -vettool=$(which gotohelmvet) ./charts/...
+package main
+
+import (
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(gotohelm.Analyzer)
+}
@@ -0,0 +1,299 @@
+package gotohelm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// Analyzer reports any go code that can not be transpiled by gotohelm. It
+// runs the transpiler itself, in [TranspileOptions.Diagnose] mode, so the
+// support rules are always in sync with gotohelm.
+//
+// Only packages that import helmette are considered to be charts and are
+// analyzed. Test files and main packages are ignored. Calls registered with
+// [RegisterCall] are supported, those of [TranspileOptions.Calls] are not.
+//
+// Analyzer may be used via `go vet -vettool` (See cmd/gotohelmvet) or any
+// other driver of [analysis.Analyzer]s, such as gopls.
+var Analyzer = &analysis.Analyzer{
+	Name:      "gotohelm",
+	Doc:       "report go code that can not be transpiled into helm templates by gotohelm",
+	URL:       "https://pkg.go.dev/github.com/redpanda-data/helm-charts/pkg/gotohelm",
+	Run:       runAnalyzer,
+	FactTypes: []analysis.Fact{new(builtinFact)},
+}
+
+// builtinFact is exported for every function with a +gotohelm:builtin
+// directive. Analyzers only have access to the syntax of the package being
+// analyzed, so the directives of dependencies are propagated as facts.
+type builtinFact struct {
+	Builtin string
+}
+
+func (*builtinFact) AFact() {}
+
+func (f *builtinFact) String() string {
+	return fmt.Sprintf("builtin(%s)", f.Builtin)
+}
+
+func runAnalyzer(pass *analysis.Pass) (any, error) {
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			if builtin := parseDirectives(fn.Doc.Text())["builtin"]; builtin != "" {
+				pass.ExportObjectFact(pass.TypesInfo.Defs[fn.Name], &builtinFact{Builtin: builtin})
+			}
+		}
+	}
+
+	if !isChart(pass.Pkg) {
+		return nil, nil
+	}
+
+	pkg, err := loadForAnalysis(pass)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newTranspiler(pkg, TranspileOptions{Diagnose: true})
+	t.pass = pass
+	t.Transpile()
+
+	for _, d := range t.diagnostics {
+		pos, end := reportPos(pass, d.Node)
+		if !pos.IsValid() {
+			continue
+		}
+
+		msg := d.Msg
+		if d.Alternative != "" {
+			msg += fmt.Sprintf(". Consider using %s instead", d.Alternative)
+		}
+
+		pass.Report(analysis.Diagnostic{
+			Pos:      pos,
+			End:      end,
+			Category: d.Kind(),
+			Message:  msg,
+		})
+	}
+
+	return nil, nil
+}
+
+// isChart returns true if the given package is a gotohelm chart or helper
+// package. That is, a non-main package that imports helmette.
+func isChart(pkg *types.Package) bool {
+	if pkg.Name() == "main" || strings.HasSuffix(pkg.Name(), "_test") {
+		return false
+	}
+	return isHelperPackage(pkg)
+}
+
+// loadForAnalysis constructs a [packages.Package] suitable for transpilation
+// from the given [analysis.Pass], akin to [LoadPackages].
+//
+// The AST rewrites performed by [LoadPackages] mutate their input, which is
+// not permitted of analyzers. Instead, the package's files are deep copied
+// before being rewritten and type checked. Copies retain the positions of
+// the originals, within pass.Fset, so diagnostics may be reported as is. As
+// pass.Files is used rather than the files on disk, unsaved editor buffers
+// are analyzed correctly.
+func loadForAnalysis(pass *analysis.Pass) (*packages.Package, error) {
+	var files []*ast.File
+	for _, f := range pass.Files {
+		if strings.HasSuffix(pass.Fset.File(f.Pos()).Name(), "_test.go") {
+			continue
+		}
+		files = append(files, cloneFile(f))
+	}
+
+	pkg := &packages.Package{
+		ID:         pass.Pkg.Path(),
+		Name:       pass.Pkg.Name(),
+		PkgPath:    pass.Pkg.Path(),
+		Fset:       pass.Fset,
+		Syntax:     files,
+		TypesSizes: pass.TypesSizes,
+	}
+
+	if err := typeCheck(pass, pkg); err != nil {
+		return nil, err
+	}
+
+	for i, parsed := range pkg.Syntax {
+		for _, rewrite := range rewrites {
+			parsed, _ = rewrite(pkg, parsed)
+		}
+		pkg.Syntax[i] = parsed
+	}
+
+	// Type check once more to populate type information of any rewritten
+	// nodes.
+	if err := typeCheck(pass, pkg); err != nil {
+		return nil, errors.Wrapf(err, "type checking rewritten package")
+	}
+
+	return pkg, nil
+}
+
+// cloneFile returns a deep copy of f. Nodes referenced multiple times (e.g.
+// comment groups or the declarations of [ast.Object]s) remain shared within
+// the copy.
+func cloneFile(f *ast.File) *ast.File {
+	type key struct {
+		typ reflect.Type
+		ptr uintptr
+	}
+
+	seen := map[key]reflect.Value{}
+
+	var clone func(v reflect.Value) reflect.Value
+	clone = func(v reflect.Value) reflect.Value {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if v.IsNil() {
+				return v
+			}
+			k := key{v.Type(), v.Pointer()}
+			if c, ok := seen[k]; ok {
+				return c
+			}
+
+			c := reflect.New(v.Type().Elem())
+			seen[k] = c
+			c.Elem().Set(clone(v.Elem()))
+			return c
+
+		case reflect.Interface:
+			if v.IsNil() {
+				return v
+			}
+			c := reflect.New(v.Type()).Elem()
+			c.Set(clone(v.Elem()))
+			return c
+
+		case reflect.Slice:
+			if v.IsNil() {
+				return v
+			}
+			c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				c.Index(i).Set(clone(v.Index(i)))
+			}
+			return c
+
+		case reflect.Map:
+			if v.IsNil() {
+				return v
+			}
+			c := reflect.MakeMapWithSize(v.Type(), v.Len())
+			for iter := v.MapRange(); iter.Next(); {
+				c.SetMapIndex(clone(iter.Key()), clone(iter.Value()))
+			}
+			return c
+
+		case reflect.Struct:
+			c := reflect.New(v.Type()).Elem()
+			for i := 0; i < v.NumField(); i++ {
+				c.Field(i).Set(clone(v.Field(i)))
+			}
+			return c
+		}
+
+		return v
+	}
+
+	return clone(reflect.ValueOf(f)).Interface().(*ast.File)
+}
+
+// typeCheck (re)populates pkg.Types and pkg.TypesInfo from pkg.Syntax,
+// resolving imports to the same [types.Package]s as pass.
+func typeCheck(pass *analysis.Pass, pkg *packages.Package) error {
+	imports := map[string]*types.Package{}
+	for _, imported := range pass.Pkg.Imports() {
+		imports[imported.Path()] = imported
+	}
+
+	config := types.Config{
+		GoVersion: pass.Pkg.GoVersion(),
+		Sizes:     pass.TypesSizes,
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if imported, ok := imports[path]; ok {
+				return imported, nil
+			}
+			return nil, errors.Newf("package %q not imported by %q", path, pass.Pkg.Path())
+		}),
+	}
+
+	pkg.TypesInfo = &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Instances:  map[*ast.Ident]types.Instance{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}
+
+	var err error
+	pkg.Types, err = config.Check(pkg.PkgPath, pkg.Fset, pkg.Syntax, pkg.TypesInfo)
+	return errors.WithStack(err)
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// reportPos returns the position of the given node to report diagnostics
+// at. Nodes introduced by the AST rewrites don't have a position, in which
+// case the position of their first positioned child is used. Positions
+// outside of the files being analyzed are not reported.
+func reportPos(pass *analysis.Pass, node ast.Node) (token.Pos, token.Pos) {
+	if !node.Pos().IsValid() {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if n == nil || node.Pos().IsValid() {
+				return false
+			}
+			if n.Pos().IsValid() {
+				node = n
+				return false
+			}
+			return true
+		})
+	}
+
+	if !node.Pos().IsValid() {
+		return token.NoPos, token.NoPos
+	}
+
+	file := pass.Fset.File(node.Pos())
+
+	for _, f := range pass.Files {
+		if pass.Fset.File(f.Pos()) != file {
+			continue
+		}
+
+		end := token.NoPos
+		if node.End().IsValid() && pass.Fset.File(node.End()) == file {
+			end = node.End()
+		}
+		return node.Pos(), end
+	}
+
+	return token.NoPos, token.NoPos
+}
@@ -0,0 +1,80 @@
+package gotohelm
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	td, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	t.Setenv("GOPATH", td)
+
+	// Every supported example must be free of diagnostics. Expected
+	// diagnostics of the unsupported package are declared via `// want`
+	// comments.
+	var patterns []string
+	for name := range testSpecs {
+		// bootstrap is not an example but internal/bootstrap.
+		if name == "bootstrap" {
+			continue
+		}
+		// Analyzer is only aware of calls registered with RegisterCall.
+		for id, fn := range testSpecs[name].Options.Calls {
+			prev, ok := calls[id]
+			t.Cleanup(func() {
+				if ok {
+					calls[id] = prev
+				} else {
+					delete(calls, id)
+				}
+			})
+			RegisterCall(id, fn)
+		}
+		patterns = append(patterns, "./"+name)
+	}
+
+	analysistest.Run(t, filepath.Join(td, "src", "example"), Analyzer, patterns...)
+}
+
+func TestCloneFile(t *testing.T) {
+	const src = `package p
+
+// F does things.
+func F(x int) int {
+	y := x
+	return y
+}
+`
+
+	fset := token.NewFileSet()
+	original, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	clone := cloneFile(original)
+
+	// Positions and comments are retained.
+	var buf bytes.Buffer
+	require.NoError(t, format.Node(&buf, fset, clone))
+	require.Equal(t, src, buf.String())
+
+	// Objects refer to the cloned declarations.
+	fn := clone.Decls[0].(*ast.FuncDecl)
+	assign := fn.Body.List[0].(*ast.AssignStmt)
+	require.Same(t, assign, fn.Body.List[1].(*ast.ReturnStmt).Results[0].(*ast.Ident).Obj.Decl)
+
+	// Mutations of the clone don't affect the original.
+	fn.Name.Name = "G"
+	fn.Body.List = nil
+	require.Equal(t, "F", original.Decls[0].(*ast.FuncDecl).Name.Name)
+	require.Len(t, original.Decls[0].(*ast.FuncDecl).Body.List, 2)
+}
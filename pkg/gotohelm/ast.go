@@ -2,6 +2,7 @@ package gotohelm
 
 import (
 	"fmt"
+	"go/token"
 	"io"
 	"strconv"
 )
@@ -149,7 +150,7 @@ func (c *Call) Write(w io.Writer) {
 	args := &DictLiteral{
 		KeysValues: []*KeyValue{
 			{
-				Key: `"a"`,
+				Key: NewLiteral("a"),
 				Value: &BuiltInCall{
 					FuncName:  "list",
 					Arguments: c.Arguments,
@@ -163,6 +164,51 @@ func (c *Call) Write(w io.Writer) {
 	fmt.Fprintf(w, `)) %q)`, "r")
 }
 
+// Closure is a function value. It pairs the name of the template of a
+// transpiled function literal with the values that it captured.
+//
+//	(list "ns.Func.func1" (list $captured))
+type Closure struct {
+	FuncName string
+	Captures []Node
+}
+
+func (c *Closure) Write(w io.Writer) {
+	fmt.Fprintf(w, "(list %q ", c.FuncName)
+	(&BuiltInCall{FuncName: "list", Arguments: c.Captures}).Write(w)
+	fmt.Fprintf(w, ")")
+}
+
+// DynamicCall is a [Call] of a function value, a [Closure], rather than a
+// named function. Captured values are passed before any other arguments.
+type DynamicCall struct {
+	Func      Node
+	Arguments []Node
+}
+
+func (c *DynamicCall) Write(w io.Writer) {
+	args := &DictLiteral{
+		KeysValues: []*KeyValue{
+			{
+				Key: NewLiteral("a"),
+				Value: &BuiltInCall{
+					FuncName: "concat",
+					Arguments: []Node{
+						&BuiltInCall{FuncName: "last", Arguments: []Node{c.Func}},
+						&BuiltInCall{FuncName: "list", Arguments: c.Arguments},
+					},
+				},
+			},
+		},
+	}
+
+	fmt.Fprintf(w, `(get (fromJson (include (first `)
+	c.Func.Write(w)
+	fmt.Fprintf(w, `) `)
+	args.Write(w)
+	fmt.Fprintf(w, `)) %q)`, "r")
+}
+
 type Assignment struct {
 	LHS Node
 	New bool
@@ -195,12 +241,13 @@ func (d *DictLiteral) Write(w io.Writer) {
 }
 
 type KeyValue struct {
-	Key   string
+	Key   Node
 	Value Node
 }
 
 func (p *KeyValue) Write(w io.Writer) {
-	fmt.Fprintf(w, "%s ", p.Key)
+	p.Key.Write(w)
+	w.Write([]byte(" "))
 	p.Value.Write(w)
 }
 
@@ -223,13 +270,17 @@ func (f *File) Write(w io.Writer) {
 }
 
 type Func struct {
-	Namespace  string
-	Name       string
+	Namespace string
+	Name      string
+	// Source is the position of the go function this Func was transpiled
+	// from.
+	Source     token.Position
 	Params     []Node
 	Statements []Node
 }
 
 func (f *Func) Write(w io.Writer) {
+	mark(w, f.Source)
 	fmt.Fprintf(w, "{{- define %q -}}\n", f.Namespace+"."+f.Name)
 	for i := range f.Params {
 		fmt.Fprintf(w, "{{- ")
@@ -267,6 +318,26 @@ func (l *Literal) Write(w io.Writer) {
 	fmt.Fprintf(w, "%s", l.Value)
 }
 
+// Sourced annotates a statement with the position of the go source it was
+// transpiled from. It's otherwise transparent.
+type Sourced struct {
+	Node
+	Pos token.Position
+}
+
+func (s *Sourced) Write(w io.Writer) {
+	mark(w, s.Pos)
+	s.Node.Write(w)
+}
+
+// unwrap returns the [Node] wrapped by a [Sourced], if any.
+func unwrap(n Node) Node {
+	if s, ok := n.(*Sourced); ok {
+		return s.Node
+	}
+	return n
+}
+
 type Block struct {
 	Statements []Node
 }
@@ -326,7 +397,7 @@ func (i *IfStmt) Write(w io.Writer) {
 
 	if i.Else != nil {
 		fmt.Fprintf(w, "{{- else -}}")
-		if _, ok := i.Else.(*IfStmt); !ok {
+		if _, ok := unwrap(i.Else).(*IfStmt); !ok {
 			fmt.Fprintf(w, "\n")
 		}
 		i.Else.Write(w)
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -15,15 +16,20 @@ var (
 	//go:embed internal/bootstrap/*.go
 	bootstrapGo embed.FS
 
-	// shims the source [File] of _shims.tpl. It's set by the init function in
-	// bootstrap.go.
+	// shims the source [File] of _shims.tpl. It's set by loadShims.
 	shims *File
+
+	// loadShims populates shims. It's called by [TranspileWithOptions] rather
+	// than an init function as loading the bootstrap package requires the
+	// working directory to be within this module, which isn't a requirement
+	// of importers that don't transpile (e.g. [Analyzer] run via go vet).
+	loadShims = sync.OnceFunc(bootstrap)
 )
 
-func init() {
-	// Oh yes. We transpile the bootstrap package when this package is first
-	// loaded to generate _shims.tpl. It's a weird process but removes any
-	// possibility of things getting out of sync.
+func bootstrap() {
+	// Oh yes. We transpile the bootstrap package when it's first needed to
+	// generate _shims.tpl. It's a weird process but removes any possibility
+	// of things getting out of sync.
 	dir, _ := os.Getwd()
 
 	// First, we always bind Dir to the working directory. It could be any
@@ -41,7 +47,9 @@ func init() {
 	}
 
 	// Then we transpile the loaded package as we would any other.
-	bootstrapChart, err := Transpile(pkgs[0])
+	// NB: transpile is used instead of Transpile to avoid recursing into
+	// loadShims.
+	bootstrapChart, err := transpile(pkgs[0], TranspileOptions{})
 	if err != nil {
 		panic(err)
 	}
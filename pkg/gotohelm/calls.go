@@ -0,0 +1,428 @@
+package gotohelm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// CallFunc transpiles calls of a function or method that is neither
+// transpiled nor bound to a template builtin. args are the transpiled
+// arguments of the call. Unsupported usages may be reported by panicking with
+// an [Unsupported].
+type CallFunc func(ctx *CallContext, args []Node) Node
+
+// CallContext is the call being transpiled by a [CallFunc].
+type CallContext struct {
+	// Call is the call expression being transpiled.
+	Call *ast.CallExpr
+	// Receiver is the transpiled receiver of method calls. It's nil for calls
+	// of functions and methods whose receiver is a package level variable of
+	// another package, such as base64.StdEncoding.
+	Receiver Node
+	// Signature is the signature of the callee with any type arguments
+	// substituted.
+	Signature *types.Signature
+
+	Fset      *token.FileSet
+	TypesInfo *types.Info
+
+	t *Transpiler
+}
+
+// Cast wraps n, if required, such that it's of the given type in templates.
+// e.g. `int` values must be cast as JSON (and sprig) may produce floats.
+func (c *CallContext) Cast(n Node, typ types.Type) Node {
+	return c.t.maybeCast(n, typ)
+}
+
+// Zero returns the zero value of typ.
+func (c *CallContext) Zero(typ types.Type) Node {
+	return c.t.zeroOf(typ)
+}
+
+// MapKey transpiles e, an expression used as the key of a map.
+func (c *CallContext) MapKey(e ast.Expr) Node {
+	return c.t.transpileMapKey(e)
+}
+
+// TypeArgs returns the type arguments of calls of generic functions.
+func (c *CallContext) TypeArgs() *types.TypeList {
+	return c.t.TypesInfo.Instances[funcIdent(c.Call.Fun)].TypeArgs
+}
+
+// Builtin transpiles the call into a call of the template builtin name, as
+// if the callee had a +gotohelm:builtin directive. Spreads (f(xs...)) must
+// be of known elements, unless name is printf, and the error of (T, error)
+// results is always nil.
+func (c *CallContext) Builtin(name string, args []Node) Node {
+	return c.t.transpileBuiltin(c.Call, c.Signature, name, args)
+}
+
+// AnnotateFailure appends the source of the call to msg, the message of a
+// failure, if [TranspileOptions.AnnotateFailures] is enabled.
+func (c *CallContext) AnnotateFailure(msg Node) Node {
+	return c.t.annotateFailure(c.Call, msg)
+}
+
+// Unsupported panics with an [Unsupported] of node.
+func (c *CallContext) Unsupported(node ast.Node, msg string) {
+	panic(&Unsupported{Node: node, Fset: c.Fset, Msg: msg})
+}
+
+// ErrorDiscarded returns true if the error result of the call is assigned to
+// the blank identifier (e.g. `x, _ := f()`).
+func (c *CallContext) ErrorDiscarded() bool {
+	return c.t.errorDiscarded(c.Call)
+}
+
+// Initializer returns the expression that v is initialized to if it's a
+// variable of the package being transpiled that is never reassigned.
+// Otherwise it returns nil.
+func (c *CallContext) Initializer(v *types.Var) ast.Expr {
+	if v.Pkg() == nil || v.Pkg().Path() != c.t.Package.PkgPath {
+		return nil
+	}
+	return c.t.initializerOf(v)
+}
+
+// TypeTest returns the equivalent of `x.(typ)`, where e transpiled to x, when
+// used as a multi-value expression (`_, ok := x.(typ)`).
+func (c *CallContext) TypeTest(e ast.Expr, typ types.Type, x Node) Node {
+	c.t.checkDispatchedAssertion(e)
+	return c.t.transpileTypeTest(c.Call, typ, x)
+}
+
+// RegisterCall registers fn to transpile calls of the function or method id,
+// replacing any existing registration. ids are the import path of the
+// callee's package followed by its name (e.g. `strings.HasPrefix` or
+// `github.com/my/pkg.Function`) or, for methods, by its receiver type in
+// parenthesis and its name (e.g. `regexp.(*Regexp).MatchString`).
+//
+// RegisterCall affects all subsequent transpilations, including those of
+// [Analyzer]. It's intended to be called from init functions. See
+// [TranspileOptions.Calls] to register calls for a single transpilation.
+func RegisterCall(id string, fn CallFunc) {
+	calls[id] = fn
+}
+
+// calls are the registered [CallFunc]s by id. It's populated with support
+// for commonly used standard library and third party functions.
+var calls = map[string]CallFunc{}
+
+func init() {
+	const helmette = "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+
+	for id, fn := range map[string]CallFunc{
+		"fmt.Sprintf":                builtinCall("printf"),
+		"golang.org/x/exp/maps.Keys": builtinCall("keys"),
+		"maps.Keys":                  builtinCall("keys"),
+		"math.Floor":                 builtinCall("floor"),
+		"strconv.Itoa":               builtinCall("toString"),
+		"strconv.Quote":              builtinCall("quote"),
+		"strings.Title":              builtinCall("title"),
+		"strings.ToLower":            builtinCall("lower"),
+		"strings.ToUpper":            builtinCall("upper"),
+		"strings.TrimSpace":          builtinCall("trim"),
+		"sort.Strings": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "sortAlpha", Arguments: args}
+		},
+		"sort.Slice": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.sort_Slice", Arguments: args}
+		},
+		"slices.Contains": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.slices_Contains", Arguments: args}
+		},
+		"slices.Index": func(ctx *CallContext, args []Node) Node {
+			return ctx.Cast(&Call{FuncName: "_shims.slices_Index", Arguments: args}, types.Typ[types.Int])
+		},
+		"slices.Sort": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.slices_Sort", Arguments: args}
+		},
+		"slices.Reverse": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.slices_Reverse", Arguments: args}
+		},
+		"slices.Compact": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.slices_Compact", Arguments: args}
+		},
+		"maps.Copy": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.maps_Copy", Arguments: args}
+		},
+		"maps.Clone": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.maps_Clone", Arguments: args}
+		},
+		"strings.TrimSuffix": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "trimSuffix", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.TrimPrefix": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "trimPrefix", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.ReplaceAll": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "replace", Arguments: []Node{args[1], args[2], args[0]}}
+		},
+		"strings.Split": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "splitList", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.SplitN": func(ctx *CallContext, args []Node) Node {
+			// sprig's splitn returns a dict rather than a list.
+			sep := &BuiltInCall{FuncName: "regexQuoteMeta", Arguments: []Node{args[1]}}
+			return &BuiltInCall{FuncName: "regexSplit", Arguments: []Node{sep, args[0], args[2]}}
+		},
+		"strings.Join": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "join", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.Contains": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "contains", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.HasPrefix": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "hasPrefix", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.HasSuffix": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "hasSuffix", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.Repeat": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "repeat", Arguments: []Node{args[1], args[0]}}
+		},
+		"strings.Fields": func(ctx *CallContext, args []Node) Node {
+			// Matches the characters considered to be spaces by
+			// unicode.IsSpace. regexFindAll returns nil rather than an empty
+			// list if there are no matches.
+			fields := &BuiltInCall{FuncName: "regexFindAll", Arguments: []Node{NewLiteral(`[^\s\v\x{85}\p{Z}]+`), args[0], &Literal{Value: "-1"}}}
+			return &BuiltInCall{FuncName: "default", Arguments: []Node{&BuiltInCall{FuncName: "list"}, fields}}
+		},
+		"strings.EqualFold": func(ctx *CallContext, args []Node) Node {
+			// An approximation of simple case folding. See doc.go.
+			return &BuiltInCall{FuncName: "eq", Arguments: []Node{
+				&BuiltInCall{FuncName: "lower", Arguments: []Node{args[0]}},
+				&BuiltInCall{FuncName: "lower", Arguments: []Node{args[1]}},
+			}}
+		},
+		"strings.Index": func(ctx *CallContext, args []Node) Node {
+			return ctx.Cast(&Call{FuncName: "_shims.strings_Index", Arguments: args}, types.Typ[types.Int])
+		},
+		"strings.Count": func(ctx *CallContext, args []Node) Node {
+			return ctx.Cast(&Call{FuncName: "_shims.strings_Count", Arguments: args}, types.Typ[types.Int])
+		},
+		"strconv.FormatInt": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.strconv_FormatInt", Arguments: args}
+		},
+		"strconv.Atoi": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.strconv_Atoi", Arguments: args}
+		},
+		"strconv.ParseBool": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.strconv_ParseBool", Arguments: args}
+		},
+		"encoding/base64.(*Encoding).EncodeToString": func(ctx *CallContext, args []Node) Node {
+			checkStdEncoding(ctx)
+			return &BuiltInCall{FuncName: "b64enc", Arguments: args}
+		},
+		"encoding/base64.(*Encoding).DecodeString": func(ctx *CallContext, args []Node) Node {
+			// Invalid input is an error in go. sprig's b64dec returns the
+			// error's message instead, so the error may only be discarded.
+			checkStdEncoding(ctx)
+			if !ctx.ErrorDiscarded() {
+				ctx.Unsupported(ctx.Call, "the error returned by (*encoding/base64.Encoding).DecodeString can't be reproduced in templates and must be discarded")
+			}
+			return &BuiltInCall{FuncName: "list", Arguments: []Node{&BuiltInCall{FuncName: "b64dec", Arguments: args}, &Literal{Value: "nil"}}}
+		},
+		"encoding/json.Marshal": func(ctx *CallContext, args []Node) Node {
+			// Values that go can't marshal (e.g. NaN) fail the template
+			// rather than being returned as an error.
+			return &BuiltInCall{FuncName: "list", Arguments: []Node{&BuiltInCall{FuncName: "mustToJson", Arguments: args}, &Literal{Value: "nil"}}}
+		},
+		"sigs.k8s.io/yaml.Marshal": func(ctx *CallContext, args []Node) Node {
+			// helm's toYaml trims the trailing newline of yaml.Marshal.
+			yaml := &BuiltInCall{FuncName: "printf", Arguments: []Node{NewLiteral("%s\n"), &BuiltInCall{FuncName: "toYaml", Arguments: args}}}
+			return &BuiltInCall{FuncName: "list", Arguments: []Node{yaml, &Literal{Value: "nil"}}}
+		},
+		"regexp.MustCompile": func(ctx *CallContext, args []Node) Node {
+			checkPattern(ctx, ctx.Call.Args[0])
+			return args[0]
+		},
+		"regexp.Compile": func(ctx *CallContext, args []Node) Node {
+			checkPattern(ctx, ctx.Call.Args[0])
+			return &BuiltInCall{FuncName: "list", Arguments: []Node{args[0], &Literal{Value: "nil"}}}
+		},
+		"regexp.MatchString": func(ctx *CallContext, args []Node) Node {
+			checkPattern(ctx, ctx.Call.Args[0])
+			return &BuiltInCall{FuncName: "list", Arguments: []Node{&BuiltInCall{FuncName: "regexMatch", Arguments: args}, &Literal{Value: "nil"}}}
+		},
+		"regexp.QuoteMeta": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexQuoteMeta", Arguments: args}
+		},
+		"regexp.(*Regexp).MatchString": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexMatch", Arguments: []Node{ctx.Receiver, args[0]}}
+		},
+		"regexp.(*Regexp).FindString": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexFind", Arguments: []Node{ctx.Receiver, args[0]}}
+		},
+		"regexp.(*Regexp).FindAllString": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexFindAll", Arguments: []Node{ctx.Receiver, args[0], args[1]}}
+		},
+		"regexp.(*Regexp).FindStringSubmatch": findStringSubmatchCall,
+		"regexp.(*Regexp).ReplaceAllString": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexReplaceAll", Arguments: []Node{ctx.Receiver, args[0], args[1]}}
+		},
+		"regexp.(*Regexp).ReplaceAllLiteralString": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexReplaceAllLiteral", Arguments: []Node{ctx.Receiver, args[0], args[1]}}
+		},
+		"regexp.(*Regexp).Split": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "regexSplit", Arguments: []Node{ctx.Receiver, args[0], args[1]}}
+		},
+		"regexp.(*Regexp).String": func(ctx *CallContext, args []Node) Node {
+			return ctx.Receiver
+		},
+		"k8s.io/apimachinery/pkg/util/intstr.FromInt32":  identityCall,
+		"k8s.io/apimachinery/pkg/util/intstr.FromInt":    identityCall,
+		"k8s.io/apimachinery/pkg/util/intstr.FromString": identityCall,
+		"k8s.io/utils/ptr.Deref": func(ctx *CallContext, args []Node) Node {
+			return ctx.Cast(&Call{FuncName: "_shims.ptr_Deref", Arguments: args}, ctx.Signature.Results().At(0).Type())
+		},
+		"k8s.io/utils/ptr.To": identityCall,
+		"k8s.io/utils/ptr.Equal": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.ptr_Equal", Arguments: args}
+		},
+		helmette + ".MustDuration": identityCall,
+		helmette + ".Dig": func(ctx *CallContext, args []Node) Node {
+			return &BuiltInCall{FuncName: "dig", Arguments: append(args[2:], args[1], args[0])}
+		},
+		helmette + ".Unwrap": func(ctx *CallContext, args []Node) Node {
+			return &Selector{Expr: args[0], Field: "AsMap"}
+		},
+		helmette + ".UnmarshalInto": identityCall,
+		helmette + ".Compact2":      compactCall,
+		helmette + ".Compact3":      compactCall,
+		helmette + ".AsIntegral": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.asintegral", Arguments: args}
+		},
+		helmette + ".AsNumeric": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.asnumeric", Arguments: args}
+		},
+		helmette + ".DictTest": func(ctx *CallContext, args []Node) Node {
+			valueType := ctx.TypeArgs().At(1)
+			return &Call{FuncName: "_shims.dicttest", Arguments: []Node{args[0], ctx.MapKey(ctx.Call.Args[1]), ctx.Zero(valueType)}}
+		},
+		helmette + ".Merge":   mergeCall,
+		helmette + ".MergeTo": mergeCall,
+		helmette + ".(Values).AsMap": func(ctx *CallContext, args []Node) Node {
+			return &Selector{Expr: ctx.Receiver, Field: "AsMap"}
+		},
+		helmette + ".Lookup": lookupCall,
+		helmette + ".TypeTest": func(ctx *CallContext, args []Node) Node {
+			return ctx.TypeTest(ctx.Call.Args[0], ctx.Signature.Results().At(0).Type(), args[0])
+		},
+		helmette + ".Fail": func(ctx *CallContext, args []Node) Node {
+			return ctx.Builtin("fail", []Node{ctx.AnnotateFailure(args[0])})
+		},
+		helmette + ".Required": func(ctx *CallContext, args []Node) Node {
+			return ctx.Builtin("required", []Node{ctx.AnnotateFailure(args[0]), args[1]})
+		},
+
+		// Support for resource.Quantity. In helm world, resource.Quantity is
+		// always represented as it's JSON representation of either a string
+		// or a number with polyfills in the bootstrap package for the
+		// following methods.
+		// WARNING: There is not 100% compatibility and this is on purpose.
+		"k8s.io/apimachinery/pkg/api/resource.MustParse": func(ctx *CallContext, args []Node) Node {
+			return &Call{FuncName: "_shims.resource_MustParse", Arguments: args}
+		},
+		"k8s.io/apimachinery/pkg/api/resource.(*Quantity).Value": func(ctx *CallContext, args []Node) Node {
+			return &Cast{To: "int64", X: &Call{FuncName: "_shims.resource_Value", Arguments: append([]Node{ctx.Receiver}, args...)}}
+		},
+		"k8s.io/apimachinery/pkg/api/resource.(*Quantity).MilliValue": func(ctx *CallContext, args []Node) Node {
+			return &Cast{To: "int64", X: &Call{FuncName: "_shims.resource_MilliValue", Arguments: append([]Node{ctx.Receiver}, args...)}}
+		},
+		"k8s.io/apimachinery/pkg/api/resource.(*Quantity).String": func(ctx *CallContext, args []Node) Node {
+			// Similarly to DeepCopy, we're exploit the JSON representation of
+			// resource.Quantity here and rely on MustParse to simply
+			// normalize the representation.
+			return &Call{FuncName: "_shims.resource_MustParse", Arguments: []Node{ctx.Receiver}}
+		},
+		"k8s.io/apimachinery/pkg/api/resource.(Quantity).DeepCopy": func(ctx *CallContext, args []Node) Node {
+			// DeepCopy is supported in a bit of a hacky way. We call
+			// "MustParse" which takes the JSON (string) representation of a
+			// resource.Quantity and parses it returning a new
+			// resource.Quantity, which is functionally equivalent. It has the
+			// added benefit of normalizing the string form of the Quantity.
+			return &Call{FuncName: "_shims.resource_MustParse", Arguments: []Node{ctx.Receiver}}
+		},
+	} {
+		RegisterCall(id, fn)
+	}
+}
+
+// builtinCall returns a [CallFunc] that transpiles calls into calls of the
+// template builtin name. See [CallContext.Builtin].
+func builtinCall(name string) CallFunc {
+	return func(ctx *CallContext, args []Node) Node {
+		return ctx.Builtin(name, args)
+	}
+}
+
+// checkStdEncoding panics with an [Unsupported] if the receiver of the call,
+// a method of [base64.Encoding], isn't base64.StdEncoding, the only encoding
+// supported by sprig.
+func checkStdEncoding(ctx *CallContext) {
+	x := ctx.Call.Fun.(*ast.SelectorExpr).X
+	if base64Encoding(ctx.TypesInfo, x) != "StdEncoding" {
+		panic(&Unsupported{
+			Node:        x,
+			Fset:        ctx.Fset,
+			Msg:         "only base64.StdEncoding is supported",
+			Alternative: "base64.StdEncoding",
+		})
+	}
+}
+
+// identityCall transpiles calls that return their only argument as is in
+// templates.
+func identityCall(ctx *CallContext, args []Node) Node {
+	return args[0]
+}
+
+func compactCall(ctx *CallContext, args []Node) Node {
+	return &Call{FuncName: "_shims.compact", Arguments: args}
+}
+
+func mergeCall(ctx *CallContext, args []Node) Node {
+	dict := DictLiteral{}
+	return &BuiltInCall{FuncName: "merge", Arguments: append([]Node{&dict}, args...)}
+}
+
+func lookupCall(ctx *CallContext, args []Node) Node {
+	// Super ugly but it's fairly safe to assume that the return type of
+	// Lookup will always be a pointer as only pointers implement
+	// kube.Object.
+	// Type params are difficult to work with so its easiest to extract the
+	// return value (Which it a generic in Lookup) of the "instance" of the
+	// function signature.
+	k8sType := ctx.Signature.Results().At(0).Type().(*types.Pointer).Elem().(*types.Named).Obj()
+
+	// Step through the client set's Scheme to automatically infer the
+	// APIVersion and Kind of objects. We don't want any accidental typos
+	// or mistyping to occur.
+	for gvk, typ := range scheme.Scheme.AllKnownTypes() {
+		if typ.PkgPath() == k8sType.Pkg().Path() && typ.Name() == k8sType.Name() {
+			apiVersion, kind := gvk.ToAPIVersionAndKind()
+
+			// Inject the apiVersion and kind as arguments and snip `dot`
+			// from the arguments list.
+			args := append([]Node{NewLiteral(apiVersion), NewLiteral(kind)}, args[1:]...)
+
+			return &Call{FuncName: "_shims.lookup", Arguments: args}
+		}
+	}
+
+	// If we couldn't find the object in the scheme, panic. It's probably
+	// due to the usage of a 3rd party resource. If you hit this, just
+	// inject a Scheme into the transpiler instead of relying on the kube
+	// client's builtin scheme.
+	panic(&Unsupported{
+		Node: ctx.Call,
+		Fset: ctx.Fset,
+		Msg:  fmt.Sprintf("unrecognized type: %v", k8sType),
+	})
+}
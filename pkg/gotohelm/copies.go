@@ -0,0 +1,264 @@
+package gotohelm
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// Go copies struct and array values upon assignment and when passing them as
+// arguments, whereas templates pass dicts and lists by reference. To preserve
+// go's value semantics, such copies are transpiled into calls of `deepCopy`.
+//
+// Deep copying is expensive, so copies are elided when neither the copy nor
+// the original value are ever mutated. See [funcBody.mutates].
+
+// isCopied returns true if values of typ are copied upon assignment in go but
+// are shared in templates.
+func (t *Transpiler) isCopied(typ types.Type) bool {
+	switch t.subst(typ).Underlying().(type) {
+	case *types.Struct, *types.Array:
+		return true
+	}
+	return false
+}
+
+// rootVar returns the variable that e is, or is a field, element, or
+// dereference of. It returns nil if e is not rooted in a variable, such as
+// the result of a call or composite literal, in which case e is a new value
+// that need not be copied.
+func rootVar(info *types.Info, e ast.Expr) *types.Var {
+	for {
+		switch x := e.(type) {
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.SelectorExpr:
+			if isPackageVar(info.ObjectOf(x.Sel)) {
+				// Package level variables are re-evaluated upon every
+				// reference, thus always new values.
+				return nil
+			}
+			e = x.X
+		case *ast.IndexExpr:
+			e = x.X
+		case *ast.StarExpr:
+			e = x.X
+		case *ast.Ident:
+			v, ok := info.ObjectOf(x).(*types.Var)
+			if !ok || isPackageVar(v) {
+				return nil
+			}
+			return v
+		default:
+			return nil
+		}
+	}
+}
+
+// copyValue wraps n, the transpilation of e, in a call to `deepCopy` if e is
+// a variable (or a field, element, etc thereof) of type typ that go would
+// copy. dst is the variable being copied into, if known, and dstBody is the
+// body of the function that declares it.
+func (t *Transpiler) copyValue(e ast.Expr, typ types.Type, n Node, dst *types.Var, dstBody *funcBody) Node {
+	if !t.isCopied(typ) {
+		return n
+	}
+
+	src := rootVar(t.TypesInfo, e)
+	if src == nil {
+		return n
+	}
+
+	// If neither the copy nor the original are ever mutated, they may safely
+	// share the same value.
+	if dst != nil && dstBody != nil && t.funcBody != nil && !dstBody.mutates(dst, true) && !t.funcBody.mutates(src, false) {
+		return n
+	}
+
+	return &BuiltInCall{FuncName: "deepCopy", Arguments: []Node{n}}
+}
+
+// funcBody is the body of a function declaration and the type information of
+// the package that declares it.
+type funcBody struct {
+	Body *ast.BlockStmt
+	Info *types.Info
+}
+
+// bodyOf returns the body of the declaration of fn or nil if it's
+// unavailable.
+func (t *Transpiler) bodyOf(fn *types.Func) *funcBody {
+	pkg, ok := t.packages[fn.Pkg().Path()]
+	if !ok || len(pkg.Syntax) == 0 {
+		return nil
+	}
+
+	if decl := findNearest[*ast.FuncDecl](pkg, fn.Origin().Pos()); decl != nil && decl.Body != nil {
+		return &funcBody{Body: decl.Body, Info: pkg.TypesInfo}
+	}
+	return nil
+}
+
+// mutates returns true if v may be mutated within body. That is if any of
+// its fields or elements are assigned to, its address is taken, a method
+// with a pointer receiver is called upon it, or it's passed to a function as
+// a pointer, map, slice or interface. If escapes is true, v is also
+// considered mutated if it, or any non-basic field of it, escapes into
+// another variable, composite literal, return value, or a call of a function
+// that isn't transpiled.
+//
+// Reassigning v itself is not a mutation as it doesn't affect any values
+// that v was copied to or from.
+func (b *funcBody) mutates(v *types.Var, escapes bool) bool {
+	// isV returns true if e is rooted in v, excluding v itself if
+	// excludeSelf is true.
+	isV := func(e ast.Expr, excludeSelf bool) bool {
+		if _, ok := ast.Unparen(e).(*ast.Ident); ok && excludeSelf {
+			return false
+		}
+		return rootVar(b.Info, e) == v
+	}
+
+	// escaping returns true if e is rooted in v and isn't a basic value.
+	escaping := func(e ast.Expr) bool {
+		if !escapes || !isV(e, false) {
+			return false
+		}
+		_, basic := b.Info.TypeOf(e).Underlying().(*types.Basic)
+		return !basic
+	}
+
+	mutated := false
+	ast.Inspect(b.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				mutated = mutated || isV(lhs, true)
+			}
+			for i, rhs := range n.Rhs {
+				// Discarded values don't escape.
+				if len(n.Lhs) == len(n.Rhs) && isBlank(n.Lhs[i]) {
+					continue
+				}
+				mutated = mutated || escaping(rhs)
+			}
+
+		case *ast.IncDecStmt:
+			mutated = mutated || isV(n.X, true)
+
+		case *ast.UnaryExpr:
+			mutated = mutated || (n.Op == token.AND && isV(n.X, false))
+
+		case *ast.ValueSpec:
+			for _, value := range n.Values {
+				mutated = mutated || escaping(value)
+			}
+
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				mutated = mutated || escaping(result)
+			}
+
+		case *ast.CompositeLit:
+			for _, el := range n.Elts {
+				if kv, ok := el.(*ast.KeyValueExpr); ok {
+					el = kv.Value
+				}
+				mutated = mutated || escaping(el)
+			}
+
+		case *ast.CallExpr:
+			mutated = mutated || b.callMutates(n, v.Pkg(), isV, escaping)
+		}
+
+		return !mutated
+	})
+
+	return mutated
+}
+
+// callMutates returns true if the call n may mutate a variable of pkg, as
+// determined by isV and escaping. See [funcBody.mutates].
+func (b *funcBody) callMutates(n *ast.CallExpr, pkg *types.Package, isV func(ast.Expr, bool) bool, escaping func(ast.Expr) bool) bool {
+	// shared returns true if e is rooted in v and refers to values that
+	// the callee may mutate through, regardless of whether it's transpiled.
+	shared := func(e ast.Expr) bool {
+		if !isV(e, false) {
+			return false
+		}
+		switch b.Info.TypeOf(e).Underlying().(type) {
+		case *types.Pointer, *types.Map, *types.Slice, *types.Interface:
+			return true
+		}
+		return false
+	}
+
+	fn, ok := b.Info.Uses[funcIdentOrNil(n.Fun)].(*types.Func)
+	if !ok {
+		// Builtins (e.g. append), conversions, and function values.
+		tv := b.Info.Types[n.Fun]
+		for _, arg := range n.Args {
+			if escaping(arg) || (!tv.IsBuiltin() && !tv.IsType() && shared(arg)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	signature := fn.Type().(*types.Signature)
+
+	// Methods with pointer receivers may mutate their receiver.
+	if recv := signature.Recv(); recv != nil {
+		if _, ok := recv.Type().(*types.Pointer); ok {
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && isV(sel.X, false) {
+				return true
+			}
+		}
+	}
+
+	// Any function may mutate values that are shared through pointers, maps,
+	// slices or interfaces.
+	if sel, ok := n.Fun.(*ast.SelectorExpr); ok && signature.Recv() != nil && shared(sel.X) {
+		return true
+	}
+	for _, arg := range n.Args {
+		if shared(arg) {
+			return true
+		}
+	}
+
+	// Transpiled functions copy any other values they mutate. Any others may
+	// retain or mutate their arguments.
+	if fn.Pkg() != nil && (fn.Pkg() == pkg || isHelperPackage(fn.Pkg())) {
+		return false
+	}
+
+	for _, arg := range n.Args {
+		if escaping(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// funcIdentOrNil returns the identifier of the function referenced by fun or
+// nil if fun isn't a, possibly qualified or instantiated, identifier.
+func funcIdentOrNil(fun ast.Expr) *ast.Ident {
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	case *ast.IndexExpr:
+		return funcIdentOrNil(fun.X)
+	case *ast.IndexListExpr:
+		return funcIdentOrNil(fun.X)
+	}
+	return nil
+}
+
+// isBlank returns true if e is the blank identifier.
+func isBlank(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
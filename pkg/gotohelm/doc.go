@@ -16,17 +16,155 @@
 // into `define` blocks. Return values are wrapped in a dictionary and
 // marshalled to JSON. (Almost like Internet Explorer circa 2011).
 // Function calls are then a pipline of `(include NAME ARGS...) | fromJson | get RETURNKEY`
+// Variadic arguments are packed into a single list. Spreads (f(xs...)) of
+// functions bound to template builtins are expanded into individual arguments
+// and thus must be slice literals or appends onto them. The exception is
+// `printf` (e.g. fmt.Sprintf), which accepts spreads of up to 10 elements of
+// any slice.
+//
+// # Helper Packages
+// Any package, other than helmette, that imports helmette may be called from a
+// chart. Such helper packages are transpiled transitively into a
+// `_<namespace>.tpl` file alongside the chart. Namespaces default to the
+// package name and may be overridden with a `+gotohelm:namespace=` directive.
+// They must be unique within a chart.
+//
+// # Closures
+// Function literals are lowered into additional `define` blocks named after
+// their enclosing function (e.g. `chart.Fn.func1`) or package level variable.
+// Function values, be they literals or named functions, are represented as a
+// list of the define's name and any captured variables, which are passed
+// ahead of the function's arguments when called. Captured maps and structs
+// are shared by reference, all other captures are copies. Function literals
+// may not assign to captured variables, nor may captured variables be
+// reassigned after the literal.
+//
+// # Generics
+// Generic functions, and methods of generic types, are transpiled once per
+// instantiation as zero values, casts, and type tests all depend on the type
+// arguments. The type arguments are mangled into the name of each `define`
+// (e.g. `chart.firstOr[int]` or `chart.Pair[string, int].Swap`).
+//
+// # Interfaces
+// Methods called through interfaces declared in transpiled packages are
+// dispatched on a hidden type tag. Values converted to such interfaces are
+// represented as a list of their type's name and the value itself (e.g.
+// `(list "chart.KafkaListeners" $value)`), which is removed upon conversion
+// to any other interface, such as `any`. Each method of the interface is
+// transpiled into a `define` (e.g. `chart.Listener.Listeners`) that calls the
+// method of the tagged type. Implementations must be declared in the chart or
+// a helper package. Type assertions on such interfaces are not supported.
+// Tags aren't removed by `toJson` or `toYaml`, so interface values held by
+// fields, slices, or maps serialize as their tagged lists rather than as the
+// values themselves. Use fields, slices, or maps of `any` for values that are
+// serialized.
+//
+// # Package Level Variables
+// Package level variables are transpiled into `define` blocks of no arguments
+// that return the variable's initial value (e.g. `chart.defaultLabels`). Every
+// reference re-evaluates the initializer, so package level variables may not
+// be assigned to. Constants, including typed iota blocks, are inlined.
+//
+// # Value Semantics
+// Go copies structs and arrays upon assignment and when passing them as
+// arguments or receivers, whereas templates share dicts and lists by reference.
+// Such copies are transpiled into `deepCopy`, unless neither the copy nor the
+// original are ever mutated.
+//
+// `eq` errors upon comparing dicts, lists, or values of differing kinds, so
+// structs, arrays, and interfaces are compared by their JSON representations
+// (e.g. `eq (toJson x) (toJson y)`). Pointers to structs and arrays may only
+// be compared to nil. Structs are merged onto their zero value beforehand, as
+// those decoded from .Values may omit zero valued fields. JSON erases some
+// distinctions that go makes:
+//   - Numbers of differing types are equal if their values are, so
+//     `any(1) == any(1.0)` is true in templates but false in go.
+//   - Structs held by interfaces or arrays aren't merged onto their zero
+//     value, so they're unequal to their literals if they omit zero valued
+//     fields.
+//   - Fields that are omitted when empty are unequal to explicitly empty
+//     fields of the same name.
+//
+// # Standard Library
+// Commonly used functions of `strings`, `strconv`, `slices`, `maps`, and
+// `sort` are lowered onto their sprig equivalents with arguments reordered as required (e.g.
+// `strings.HasPrefix(s, p)` is `hasPrefix p s`) or onto re-implementations in
+// `_shims.tpl`. strings.EqualFold compares the lowercased strings rather than
+// their Unicode simple case foldings, so it differs from go for characters
+// such as "ß" and "ẞ" or "K" and the Kelvin sign "K". The errors of
+// strconv.Atoi and strconv.ParseBool are represented by their messages, so
+// they may be compared to nil or formatted but their methods may not be
+// called. Lists can't be modified in templates, so functions that modify
+// slices in place (e.g. `slices.Sort(x)`, `sort.Slice(x, less)`) return the
+// modified slice, which is assigned back to x. Assignments to elements of
+// slices (e.g. `x[i] = v` or `x[i] += v`) are lowered likewise. Other
+// references to x's elements are left unmodified.
+//
+// Byte slices converted from strings are represented as strings, as they are
+// by sprig, such that json.Marshal, sigs.k8s.io/yaml.Marshal, and
+// base64.StdEncoding are lowered onto `toJson`, `toYaml`, `b64enc`, and
+// `b64dec`. `b64dec` returns the error's message upon invalid input, so the
+// error of DecodeString must be discarded. Values that json.Marshal can't
+// marshal fail the template. SHA-256 digests are computed with
+// [helmette.Sha256Sum].
+//
+// Compiled regular expressions are represented by their pattern and their
+// methods are lowered onto sprig's `regex*` functions. Constant patterns are
+// compiled upon transpilation, so syntax that go's regexp (RE2) doesn't
+// support, such as lookarounds, is reported as [Unsupported].
+// FindStringSubmatch requires a constant pattern.
+//
+// # Registered Calls
+// Support for functions and methods of packages that aren't transpiled,
+// including all of the above, is a table of [CallFunc]s keyed by the callee's
+// id (e.g. `strings.HasPrefix` or `regexp.(*Regexp).MatchString`). A
+// [CallFunc] receives the call's transpiled arguments and returns the [Node]
+// to emit in its place. [CallContext] provides the capabilities that the
+// defaults rely on, such as lowering onto template builtins
+// ([CallContext.Builtin]). Chart repositories may teach gotohelm about their
+// own or third party packages with [RegisterCall], from an init function of
+// the binary that runs gotohelm, or [TranspileOptions.Calls]. Only the former
+// are known to [Analyzer]. Registered calls take precedence over
+// +gotohelm:builtin directives and may replace the defaults.
 //
 // # Interop
 // Transpiled go functions can be invoked within existing templates using the
 // following syntax: `((include NAME (dict "a" (list ARGS...))) | fromJson | get "r")`
 //
+// # Analysis
+// [Analyzer] reports code that can't be transpiled without running gotohelm
+// itself. It's usable within gopls or via go vet:
+// `go vet -vettool=$(which gotohelmvet) ./...`
+//
+// # Source Maps
+// [File.WriteWithSourceMap] records the go source of every transpiled function
+// and statement. [ResolveRenderError] uses the resulting [SourceMap]s to map
+// the position of a helm rendering error back to the go code that caused it.
+// See the -source-maps flag of cmd/gotohelm.
+//
+// # Failures
+// panic, [helmette.Fail] and [helmette.Required] are transpiled into `fail`
+// and `required`, which only report their message. Enabling
+// [TranspileOptions.AnnotateFailures] (-annotate-failures) appends the go
+// function and file:line that raised the failure. Go code may produce the
+// same messages by recovering with [helmette.AnnotateFailure]. The runtime
+// names of function literals depend on inlining, so failures raised within
+// them are not annotated.
+//
 // # Limitations
 //   - There is no "trap door" to fallback to raw templates
-//   - Switch statements, in all forms, are not currently supported
+//   - Switch statements are lowered into if-else chains. Type switches may
+//     not include numeric types.
+//   - For loops that aren't a simple count (e.g. `for i := 0; i < n; i++`)
+//     are lowered into a range that fails the template after 10000
+//     iterations.
 //   - Code must deal with the "lowest common denominator" of .Values in the form
 //     of map[string]any. Values coalescing has not yet been implemented.
-//   - Type assertions don't work.
+//   - Type assertions may not assert numeric types or interfaces with
+//     methods.
 //   - Many helpers and bits of syntax are missing.
-//   - Most forms of incompatibility are handled with panics and fmt.Sprintf.
+//   - Unsupported syntax is reported as an [Unsupported] error. By default,
+//     transpilation stops at the first one. [TranspileOptions.Diagnose]
+//     collects every one into [Diagnostics] instead. Some panics remain for
+//     unexpected internal states.
 package gotohelm
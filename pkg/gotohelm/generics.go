@@ -0,0 +1,243 @@
+package gotohelm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// instance is a single instantiation of a generic function or a method of a
+// generic type. Templates are untyped but zero values, casts, and type tests
+// are not, so generic functions are transpiled once per instance.
+type instance struct {
+	// Func is the generic function being instantiated. i.e. its origin.
+	Func *types.Func
+	// Name is the transpiled name of this instance. The type arguments are
+	// mangled into the name of the function. e.g. firstOr[int] or
+	// Pair[int, string].Swap.
+	Name string
+	// TypeArgs maps the type parameters of Func to the concrete types they
+	// have been instantiated with.
+	TypeArgs map[*types.TypeParam]types.Type
+}
+
+// instances is the set of generic function instances required by a chart. It
+// is shared amongst the [Transpiler]s of a chart and its helper packages as
+// generic functions may be instantiated outside of their defining package.
+type instances struct {
+	seen    map[string]bool
+	pending map[*types.Package][]*instance
+}
+
+func newInstances() *instances {
+	return &instances{
+		seen:    map[string]bool{},
+		pending: map[*types.Package][]*instance{},
+	}
+}
+
+// isGeneric returns true if the given function declaration has type
+// parameters or is a method of a generic type.
+func isGeneric(decl *ast.FuncDecl) bool {
+	if decl.Type.TypeParams != nil {
+		return true
+	}
+
+	if decl.Recv == nil {
+		return false
+	}
+
+	switch recv := ast.Unparen(decl.Recv.List[0].Type).(type) {
+	case *ast.StarExpr:
+		_, isIndex := ast.Unparen(recv.X).(*ast.IndexExpr)
+		_, isIndexList := ast.Unparen(recv.X).(*ast.IndexListExpr)
+		return isIndex || isIndexList
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+
+	return false
+}
+
+// calleeNameFor returns the transpiled name of fn, as referenced by fun,
+// which is either the function of a call expression or a function value. If
+// fn is generic, the name of its instance is returned and the instance is
+// queued for transpilation.
+func (t *Transpiler) calleeNameFor(fn *types.Func, fun ast.Expr) string {
+	origin := fn.Origin()
+	signature := origin.Type().(*types.Signature)
+
+	var params *types.TypeParamList
+	var args []types.Type
+
+	switch {
+	case signature.RecvTypeParams().Len() > 0:
+		// Methods of generic types are instantiated by their receiver.
+		params = signature.RecvTypeParams()
+
+		recv := t.typeOf(fun.(*ast.SelectorExpr).X)
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+
+		typeArgs := recv.(*types.Named).TypeArgs()
+		for i := 0; i < typeArgs.Len(); i++ {
+			args = append(args, typeArgs.At(i))
+		}
+
+	case signature.TypeParams().Len() > 0:
+		params = signature.TypeParams()
+
+		typeArgs := t.TypesInfo.Instances[funcIdent(fun)].TypeArgs
+		for i := 0; i < typeArgs.Len(); i++ {
+			args = append(args, t.subst(typeArgs.At(i)))
+		}
+
+	default:
+		return t.funcNameFor(origin)
+	}
+
+	inst := &instance{
+		Func:     origin,
+		Name:     mangle(t.funcNameFor(origin), signature.Recv() != nil, args),
+		TypeArgs: map[*types.TypeParam]types.Type{},
+	}
+
+	for i := 0; i < params.Len(); i++ {
+		inst.TypeArgs[params.At(i)] = args[i]
+	}
+
+	key := origin.Pkg().Path() + "." + inst.Name
+	if !t.instances.seen[key] {
+		t.instances.seen[key] = true
+		t.instances.pending[origin.Pkg()] = append(t.instances.pending[origin.Pkg()], inst)
+	}
+
+	return inst.Name
+}
+
+// declNameFor returns the transpiled name of decl, the function declaration
+// being transpiled, which is the name of the current instance if decl is
+// generic.
+func (t *Transpiler) declNameFor(decl *ast.FuncDecl) string {
+	if t.instance != nil {
+		return t.instance.Name
+	}
+	return t.funcNameFor(t.TypesInfo.ObjectOf(decl.Name).(*types.Func))
+}
+
+// transpileInstances transpiles all pending instances of this package's
+// generic functions into the [File]s that declare them. Transpiling an
+// instance may result in further instances. It returns true if any instances
+// were transpiled.
+func (t *Transpiler) transpileInstances() bool {
+	pkg := t.Package.Types
+
+	transpiled := false
+	for len(t.instances.pending[pkg]) > 0 {
+		inst := t.instances.pending[pkg][0]
+		t.instances.pending[pkg] = t.instances.pending[pkg][1:]
+
+		decl := findNearest[*ast.FuncDecl](t.Package, inst.Func.Pos())
+
+		// Generic functions within ignored files or functions are not
+		// transpiled, just as their non-generic counterparts.
+		file, ok := t.generics[decl]
+		if !ok {
+			continue
+		}
+
+		t.instance = inst
+		file.Funcs = append(file.Funcs, t.transpileFuncDecls(decl)...)
+		t.instance = nil
+
+		t.instantiated[decl] = true
+		transpiled = true
+	}
+
+	return transpiled
+}
+
+// diagnoseGenerics transpiles, and then discards, any generic functions that
+// have not been instantiated so that they are included in diagnostics.
+func (t *Transpiler) diagnoseGenerics() {
+	for decl := range t.generics {
+		if !t.instantiated[decl] {
+			t.transpileFuncDecls(decl)
+		}
+	}
+}
+
+// subst replaces any type parameters within typ with the type arguments of
+// the instance being transpiled, if any.
+func (t *Transpiler) subst(typ types.Type) types.Type {
+	if t.instance == nil {
+		return typ
+	}
+
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		if arg, ok := t.instance.TypeArgs[typ]; ok {
+			return arg
+		}
+	case *types.Pointer:
+		return types.NewPointer(t.subst(typ.Elem()))
+	case *types.Slice:
+		return types.NewSlice(t.subst(typ.Elem()))
+	case *types.Array:
+		return types.NewArray(t.subst(typ.Elem()), typ.Len())
+	case *types.Map:
+		return types.NewMap(t.subst(typ.Key()), t.subst(typ.Elem()))
+	case *types.Named:
+		if typ.TypeArgs().Len() == 0 {
+			return typ
+		}
+
+		var args []types.Type
+		for i := 0; i < typ.TypeArgs().Len(); i++ {
+			args = append(args, t.subst(typ.TypeArgs().At(i)))
+		}
+
+		instantiated, err := types.Instantiate(nil, typ.Origin(), args, false)
+		if err != nil {
+			panic(err)
+		}
+		return instantiated
+	}
+
+	return typ
+}
+
+// mangle returns the name of an instance of the function name with the given
+// type arguments. Type arguments of methods are attributed to their receiver.
+// e.g. firstOr[int] or Pair[int, string].Swap
+func mangle(name string, isMethod bool, args []types.Type) string {
+	var typeArgs []string
+	for _, arg := range args {
+		typeArgs = append(typeArgs, types.TypeString(arg, (*types.Package).Name))
+	}
+
+	mangled := fmt.Sprintf("[%s]", strings.Join(typeArgs, ", "))
+
+	if isMethod {
+		return strings.Replace(name, ".", mangled+".", 1)
+	}
+	return name + mangled
+}
+
+// funcIdent returns the identifier of the function referenced by fun. e.g.
+// firstOr, firstOr[int], or helpers.FirstOr[int].
+func funcIdent(fun ast.Expr) *ast.Ident {
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	case *ast.IndexExpr:
+		return funcIdent(fun.X)
+	case *ast.IndexListExpr:
+		return funcIdent(fun.X)
+	}
+	panic(fmt.Sprintf("unhandled function expression: %T", fun))
+}
@@ -0,0 +1,92 @@
+package helmette
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// helmettePkg is the import path of this package. Frames from within it are
+// skipped when searching for the source of a failure so that Fail and
+// Required are attributed to their callers.
+var helmettePkg = reflect.TypeOf(Dot{}).PkgPath()
+
+// FailureSource returns the suffix appended to `fail` and `required` messages
+// when gotohelm's TranspileOptions.AnnotateFailures is enabled. function is
+// the fully qualified go function name, as reported by [runtime.Frame], and
+// file is the import path of the package joined with the file's base name.
+func FailureSource(function, file string, line int) string {
+	return fmt.Sprintf("\n\tat %s (%s:%d)", function, file, line)
+}
+
+// closureRE matches the runtime names of function literals. e.g.
+// pkg.Fn.func1, pkg.Fn.func1.2, or pkg.init.func1. Function literals are
+// additionally named after any function they're inlined into, which gotohelm
+// can't predict, so failures within them are not annotated.
+var closureRE = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// AnnotateFailure is the go equivalent of gotohelm's
+// TranspileOptions.AnnotateFailures. It appends the [FailureSource] of the
+// panic being recovered to recovered, if recovered is a string. Other values,
+// such as runtime errors, and panics raised within function literals or the
+// standard library, which templates implement as shims, are returned as is.
+//
+// AnnotateFailure inspects the call stack of the panic and therefore MUST be
+// called from the deferred function that recovered it.
+//
+//	defer func() { err = helmette.AnnotateFailure(recover()) }()
+func AnnotateFailure(recovered any) any {
+	msg, ok := recovered.(string)
+	if !ok {
+		return recovered
+	}
+
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+
+	// The frames prior to runtime.gopanic are that of the deferred function.
+	// The first frame thereafter, outside of helmette, is the source of the
+	// panic.
+	panicking := false
+	for {
+		frame, more := frames.Next()
+
+		if panicking && !strings.HasPrefix(frame.Function, helmettePkg+".") {
+			pkgPath := funcPkgPath(frame.Function)
+			if closureRE.MatchString(frame.Function) || isStd(pkgPath) {
+				return recovered
+			}
+
+			file := path.Join(pkgPath, filepath.Base(frame.File))
+			return msg + FailureSource(frame.Function, file, frame.Line)
+		}
+
+		panicking = panicking || frame.Function == "runtime.gopanic"
+
+		if !more {
+			return recovered
+		}
+	}
+}
+
+// funcPkgPath returns the import path of the package that the fully
+// qualified function name fn belongs to.
+// e.g. example.com/pkg.(*Type).Method -> example.com/pkg
+func funcPkgPath(fn string) string {
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+	return fn
+}
+
+// isStd returns true if pkgPath is the import path of a standard library
+// package. That is if its first element doesn't contain a dot.
+func isStd(pkgPath string) bool {
+	first, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".")
+}
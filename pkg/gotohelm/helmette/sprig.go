@@ -1,6 +1,8 @@
 package helmette
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -275,6 +277,14 @@ func ToJSON(value any) string {
 	return string(marshalled)
 }
 
+// Sha256Sum is the go equivalent of sprig's `sha256sum`. It returns the hex
+// encoded SHA-256 digest of input.
+// +gotohelm:builtin=sha256sum
+func Sha256Sum(input string) string {
+	digest := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(digest[:])
+}
+
 // MustToJSON is the go equivalent of sprig's `mustToJson`.
 // +gotohelm:builtin=mustToJson
 func MustToJSON(value any) string {
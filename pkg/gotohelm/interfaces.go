@@ -0,0 +1,277 @@
+package gotohelm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// Templates have no notion of types, so calls of methods through interfaces
+// are dispatched on a hidden type tag. Values of dispatched interfaces (see
+// [Transpiler.isDispatched]) are represented as a pair of the tag of their
+// dynamic type and the value itself:
+//
+//	var l Listener = KafkaListeners{}
+//	// $l := (list "redpanda.KafkaListeners" (dict ...))
+//
+// Each method of a dispatched interface is transpiled into a `define` that
+// calls the method of the same name on the dynamic type of its receiver:
+//
+//	{{- define "redpanda.Listener.Listeners" -}}
+//	{{- if (eq (index $x 0) "redpanda.KafkaListeners") -}}
+//	...
+//
+// The tag is removed when such values are converted to any other interface,
+// such as `any`.
+
+// isDispatched returns true if values of typ are tagged with their dynamic
+// type. That is if typ is a non-generic interface with methods declared
+// within a transpiled package.
+func (t *Transpiler) isDispatched(typ types.Type) bool {
+	named, ok := t.subst(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.TypeArgs().Len() > 0 {
+		return false
+	}
+
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return false
+	}
+
+	pkg := named.Obj().Pkg()
+	return pkg.Path() == t.Package.PkgPath || isHelperPackage(pkg)
+}
+
+// typeTag returns the tag of values of typ when converted to a dispatched
+// interface. Values and pointers share the same tag as they share the same
+// representation in templates.
+func (t *Transpiler) typeTag(n ast.Node, typ types.Type) string {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.TypeArgs().Len() > 0 {
+		panic(&Unsupported{
+			Node: n,
+			Fset: t.Fset,
+			Msg:  fmt.Sprintf("only non-generic named types may be converted to interfaces with methods. Got %v", typ),
+		})
+	}
+
+	return t.namespaceFor(named.Obj().Pkg()) + "." + named.Obj().Name()
+}
+
+// convert converts n, the transpilation of e, to the type to just as go
+// implicitly would upon assigning e to a variable of type to. Values
+// converted to dispatched interfaces are tagged with their type and untagged
+// once converted to any other interface.
+func (t *Transpiler) convert(e ast.Expr, to types.Type, n Node) Node {
+	if to == nil {
+		return n
+	}
+
+	from := t.typeOf(e)
+	to = t.subst(to)
+
+	switch {
+	case isUntypedNil(from):
+		return n
+
+	case t.isDispatched(to) && !types.IsInterface(from):
+		return &BuiltInCall{FuncName: "list", Arguments: []Node{NewLiteral(t.typeTag(e, from)), n}}
+
+	case t.isDispatched(from) && types.IsInterface(to) && !t.isDispatched(to):
+		// Interfaces may be nil, which has no tag to remove.
+		return &BuiltInCall{
+			FuncName: "last",
+			Arguments: []Node{
+				&BuiltInCall{
+					FuncName:  "default",
+					Arguments: []Node{&BuiltInCall{FuncName: "list", Arguments: []Node{&Nil{}}}, n},
+				},
+			},
+		}
+	}
+
+	return n
+}
+
+// convertArgs converts the arguments of the call n, which transpiled to
+// args, to the types of the parameters of n's callee.
+func (t *Transpiler) convertArgs(n *ast.CallExpr, args []Node) {
+	tv := t.TypesInfo.Types[n.Fun]
+
+	// Explicit conversions, e.g. Listener(x).
+	if tv.IsType() {
+		if len(args) == 1 {
+			args[0] = t.convert(n.Args[0], tv.Type, args[0])
+		}
+		return
+	}
+
+	signature, ok := t.typeOf(n.Fun).Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+
+	params := signature.Params()
+	for i := range args {
+		var param types.Type
+		switch {
+		case signature.Variadic() && i >= params.Len()-1:
+			param = params.At(params.Len() - 1).Type()
+			if slice, ok := param.Underlying().(*types.Slice); ok && !n.Ellipsis.IsValid() {
+				param = slice.Elem()
+			}
+		case i < params.Len():
+			param = params.At(i).Type()
+		}
+
+		args[i] = t.convert(n.Args[i], param, args[i])
+	}
+}
+
+// convertResult transpiles the i'th result of the return statement stmt and
+// converts it to the type of the corresponding result of the function being
+// transpiled.
+func (t *Transpiler) convertResult(stmt *ast.ReturnStmt, i int) Node {
+	e := stmt.Results[i]
+
+	// Returns of multi-valued calls (e.g. return f()) need no conversion.
+	if t.results == nil || t.results.Len() != len(stmt.Results) {
+		return t.transpileExpr(e)
+	}
+
+	return t.convert(e, t.results.At(i).Type(), t.transpileExpr(e))
+}
+
+// checkDispatchedAssertion panics with an [Unsupported] if x, the operand of
+// a type assertion or switch, is a tagged interface value.
+func (t *Transpiler) checkDispatchedAssertion(x ast.Expr) {
+	if t.isDispatched(t.typeOf(x)) {
+		panic(&Unsupported{
+			Node:        x,
+			Fset:        t.Fset,
+			Msg:         fmt.Sprintf("type assertions on interfaces with methods are not supported. Got %v", t.typeOf(x)),
+			Alternative: "a method of the interface",
+		})
+	}
+}
+
+// transpileInterface transpiles the methods of the interface declared by
+// spec, if it's dispatched, into `define`s that call the method of the
+// dynamic type of their receiver.
+func (t *Transpiler) transpileInterface(spec *ast.TypeSpec) (_ []*Func) {
+	defer t.recoverUnsupported(spec)
+
+	named, ok := t.TypesInfo.Defs[spec.Name].Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 || !t.isDispatched(named) {
+		return nil
+	}
+
+	iface := named.Underlying().(*types.Interface)
+	impls := t.implementationsOf(iface)
+
+	var funcs []*Func
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		method := iface.ExplicitMethod(i)
+		signature := method.Type().(*types.Signature)
+
+		recv := &Ident{Name: "x"}
+		params := []Node{recv}
+		for j := 0; j < signature.Params().Len(); j++ {
+			params = append(params, &Ident{Name: fmt.Sprintf("p%d", j)})
+		}
+
+		tag := &BuiltInCall{FuncName: "index", Arguments: []Node{recv, &Literal{Value: "0"}}}
+		value := &BuiltInCall{FuncName: "index", Arguments: []Node{recv, &Literal{Value: "1"}}}
+
+		var conds []Node
+		var bodies []*Block
+		for _, impl := range impls {
+			obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(impl), true, impl.Obj().Pkg(), method.Name())
+			fn := obj.(*types.Func)
+
+			var call Node = &Call{
+				FuncName:  t.namespaceFor(fn.Pkg()) + "." + t.funcNameFor(fn),
+				Arguments: append([]Node{value}, params[1:]...),
+			}
+			if signature.Results().Len() > 0 {
+				call = &Return{Expr: call}
+			} else {
+				call = &Statement{Expr: call}
+			}
+
+			conds = append(conds, &BuiltInCall{
+				FuncName:  "eq",
+				Arguments: []Node{tag, NewLiteral(t.typeTag(spec, impl))},
+			})
+			bodies = append(bodies, &Block{Statements: []Node{call}})
+		}
+
+		// Values of types from packages that aren't transpiled may not be
+		// dispatched.
+		conds = append(conds, nil)
+		bodies = append(bodies, &Block{Statements: []Node{
+			&Statement{Expr: &BuiltInCall{
+				FuncName: "fail",
+				Arguments: []Node{&BuiltInCall{
+					FuncName: "printf",
+					Arguments: []Node{
+						NewLiteral(fmt.Sprintf("%%s does not implement %s", t.typeTag(spec, named))),
+						tag,
+					},
+				}},
+			}},
+		}})
+
+		funcs = append(funcs, &Func{
+			Name:       t.funcNameFor(method),
+			Namespace:  t.namespaceFor(t.Package.Types),
+			Source:     t.Fset.PositionFor(method.Pos(), true),
+			Params:     params,
+			Statements: []Node{switchToIfChain(conds, bodies)},
+		})
+	}
+
+	return funcs
+}
+
+// implementationsOf returns the non-interface, non-generic named types of
+// the chart's package and all of its helper packages that implement iface,
+// either as values or pointers. Interfaces of helper packages may be
+// implemented by the chart's types.
+func (t *Transpiler) implementationsOf(iface *types.Interface) []*types.Named {
+	pkgs := []*types.Package{t.root.Types}
+	for _, helper := range helperPackages(t.root) {
+		pkgs = append(pkgs, helper.Types)
+	}
+
+	sort.Slice(pkgs[1:], func(i, j int) bool {
+		return pkgs[i+1].Path() < pkgs[j+1].Path()
+	})
+
+	var impls []*types.Named
+	for _, pkg := range pkgs {
+		// Names are returned in sorted order.
+		for _, name := range pkg.Scope().Names() {
+			obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() {
+				continue
+			}
+
+			named, ok := obj.Type().(*types.Named)
+			if !ok || types.IsInterface(named) || named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			if types.Implements(named, iface) || types.Implements(types.NewPointer(named), iface) {
+				impls = append(impls, named)
+			}
+		}
+	}
+
+	return impls
+}
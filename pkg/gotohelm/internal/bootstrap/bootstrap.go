@@ -36,6 +36,9 @@ const (
 	gibi = kibi * kibi * kibi
 	tebi = kibi * kibi * kibi * kibi
 	pebi = kibi * kibi * kibi * kibi * kibi
+
+	maxInt64 = 1<<63 - 1
+	minInt64 = -1 << 63
 )
 
 // typeatest is the implementation of the go syntax `_, _ := m.(t)`.
@@ -89,6 +92,69 @@ func _len(m any) int {
 	return Len(m)
 }
 
+// bitwiseor is the implementation of the go syntax `a | b` for integers as
+// sprig has no bitwise operators. Bits are ORed one at a time through
+// arithmetic. Negative (two's complement) values are handled by ORing the
+// sign bit separately.
+func bitwiseor(a, b int64) int64 {
+	negative := a < 0 || b < 0
+	if a < 0 {
+		a = a + maxInt64 + 1
+	}
+	if b < 0 {
+		b = b + maxInt64 + 1
+	}
+
+	result := int64(0)
+	bit := int64(1)
+	bits := 63
+	for i := 0; i < bits; i++ {
+		if (a/bit)%2 == 1 || (b/bit)%2 == 1 {
+			result = result + bit
+		}
+		// Avoid overflowing on the final iteration.
+		if i < bits-1 {
+			bit = bit * 2
+		}
+	}
+
+	if negative {
+		return result + minInt64
+	}
+	return result
+}
+
+// runes returns the byte offset and rune of each character in s as a list of
+// pairs, which is how go ranges over strings. Templates are only able to
+// index strings by byte so each character is decoded from UTF-8 by hand.
+// splitList yields each byte of invalid UTF-8 on its own, which decodes to
+// U+FFFD as it does in go.
+func runes(s string) [][]int {
+	// The bits of the leading byte that denote the length of a character and
+	// the exclusive upper bound of leading bytes of each length.
+	lead := []int{0, 0, 192, 224, 240}
+	limit := []int{0, 128, 224, 240, 248}
+
+	var out [][]int
+	offset := 0
+	for _, char := range SplitList("", s) {
+		n := Len(char)
+		valid := int(char[0]) >= lead[n] && int(char[0]) < limit[n]
+		r := int(char[0]) - lead[n]
+		for i := 1; i < n; i++ {
+			valid = valid && int(char[i]) >= 128 && int(char[i]) < 192
+			r = r*64 + int(char[i]) - 128
+		}
+
+		if !valid {
+			r = 65533
+		}
+		out = append(out, []int{offset, r})
+		offset = offset + n
+	}
+	return out
+}
+
 // re-implementation of k8s.io/utils/ptr.Deref.
 func ptr_Deref(ptr, def any) any {
 	if ptr != nil {
@@ -105,6 +171,250 @@ func ptr_Equal(a, b any) bool {
 	return a == b
 }
 
+// re-implementation of strings.Index. Byte offsets are recovered from the
+// length of the text preceding the first occurrence of substr.
+func strings_Index(s, substr string) int {
+	if substr == "" {
+		return 0
+	}
+	parts := SplitList(substr, s)
+	if Len(parts) == 1 {
+		return -1
+	}
+	return Len(parts[0])
+}
+
+// re-implementation of strings.Count.
+func strings_Count(s, substr string) int {
+	// Empty substrings occur before and after each character.
+	if substr == "" {
+		return Len(SplitList("", s)) + 1
+	}
+	return Len(SplitList(substr, s)) - 1
+}
+
+// re-implementation of fmt.Sprintf for spreads (fmt.Sprintf(format, a...))
+// of slices that aren't known until the template is rendered. Templates
+// can't spread arguments, so up to 10 elements of a are passed to printf
+// individually.
+func fmt_Sprintf(format string, a []any) string {
+	n := Len(a)
+	if n == 0 {
+		return fmt.Sprintf(format)
+	} else if n == 1 {
+		return fmt.Sprintf(format, a[0])
+	} else if n == 2 {
+		return fmt.Sprintf(format, a[0], a[1])
+	} else if n == 3 {
+		return fmt.Sprintf(format, a[0], a[1], a[2])
+	} else if n == 4 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3])
+	} else if n == 5 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3], a[4])
+	} else if n == 6 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3], a[4], a[5])
+	} else if n == 7 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3], a[4], a[5], a[6])
+	} else if n == 8 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7])
+	} else if n == 9 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8])
+	} else if n == 10 {
+		return fmt.Sprintf(format, a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8], a[9])
+	}
+	panic(fmt.Sprintf("fmt.Sprintf: spreads of more than 10 arguments are not supported. Got: %d", n))
+}
+
+// re-implementation of strconv.FormatInt. Digits are accumulated one at a
+// time as sprig only formats integers in base 10.
+func strconv_FormatInt(i int64, base int) string {
+	if base < 2 || base > 36 {
+		panic("strconv: illegal AppendInt/FormatInt base")
+	}
+
+	if i == 0 {
+		return "0"
+	}
+
+	digits := "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	// Negating minInt64 overflows, so digits are accumulated from the
+	// negative of i instead.
+	sign := "-"
+	if i > 0 {
+		sign = ""
+		i = i * -1
+	}
+
+	out := ""
+	for i < 0 {
+		digit := int((i % int64(base)) * -1)
+		out = Substr(digit, digit+1, digits) + out
+		i = i / int64(base)
+	}
+	return sign + out
+}
+
+// re-implementation of strconv.Atoi. Errors are represented by their
+// messages. sprig's atoi returns 0 for invalid syntax and clamps out of range
+// values, as strconv.Atoi does, but doesn't report either.
+func strconv_Atoi(s string) []any {
+	if !RegexMatch(`^[+-]?[0-9]+$`, s) {
+		return []any{0, fmt.Sprintf("strconv.Atoi: parsing %q: invalid syntax", s)}
+	}
+	n := Atoi(s)
+	digits := RegexReplaceAll(`^[+-]?0*`, s, "")
+	if (n == maxInt64 && digits != "9223372036854775807") || (n == minInt64 && digits != "9223372036854775808") {
+		return []any{n, fmt.Sprintf("strconv.Atoi: parsing %q: value out of range", s)}
+	}
+	return []any{n, nil}
+}
+
+// re-implementation of strconv.ParseBool. Errors are represented by their
+// messages.
+func strconv_ParseBool(s string) []any {
+	if s == "1" || s == "t" || s == "T" || s == "TRUE" || s == "true" || s == "True" {
+		return []any{true, nil}
+	}
+	if s == "0" || s == "f" || s == "F" || s == "FALSE" || s == "false" || s == "False" {
+		return []any{false, nil}
+	}
+	return []any{false, fmt.Sprintf("strconv.ParseBool: parsing %q: invalid syntax", s)}
+}
+
+// re-implementation of slices.Index.
+func slices_Index(s []any, v any) int {
+	idx := -1
+	for i, e := range s {
+		if e == v {
+			idx = i
+			break
+		}
+	}
+	return idx
+}
+
+// re-implementation of slices.Contains.
+func slices_Contains(s []any, v any) bool {
+	return slices_Index(s, v) != -1
+}
+
+// re-implementation of slices.Compact.
+func slices_Compact(s []any) []any {
+	// Empty slices are returned as is, preserving nil-ness.
+	if Empty(s) {
+		return s
+	}
+	var out []any
+	for i, e := range s {
+		if i == 0 || e != s[i-1] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// re-implementation of slices.Reverse. Lists may not be modified in place so
+// the reversed slice is returned instead.
+func slices_Reverse(s []any) []any {
+	if Empty(s) {
+		return s
+	}
+	var out []any
+	for i := range s {
+		out = append(out, s[Len(s)-1-i])
+	}
+	return out
+}
+
+// slice_Set returns a copy of s with its i'th element set to v. Lists may not
+// be modified in place so `s[i] = v` is transpiled into `s = slice_Set(s, i,
+// v)`.
+func slice_Set(s []any, i int, v any) []any {
+	if i < 0 || i >= Len(s) {
+		panic(fmt.Sprintf("runtime error: index out of range [%d] with length %d", i, Len(s)))
+	}
+	var out []any
+	for j, e := range s {
+		if j == i {
+			out = append(out, v)
+		} else {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// re-implementation of slices.Sort. Lists may not be modified in place so
+// the sorted slice is returned instead.
+func slices_Sort(s []any) []any {
+	return sortBy(s, func(i, j int) bool {
+		// Elements are either all strings or all numbers.
+		if TypeIs("string", s[i]) {
+			return ToString(s[i]) < ToString(s[j])
+		}
+		return Float64(s[i]) < Float64(s[j])
+	})
+}
+
+// re-implementation of sort.Slice. less indexes into s, which is never
+// modified, so the indices of s are sorted rather than its elements. Lists
+// may not be modified in place so the sorted slice is returned instead.
+func sort_Slice(s []any, less func(i, j int) bool) []any {
+	return sortBy(s, less)
+}
+
+// sortBy returns the elements of s in the order given by less, a comparison
+// of indices of s. It's an insertion sort, which is stable and matches go's
+// ordering of equal elements for small slices.
+func sortBy(s []any, less func(i, j int) bool) []any {
+	if Empty(s) {
+		return s
+	}
+
+	var order []int
+	for i := range s {
+		var next []int
+		inserted := false
+		for _, j := range order {
+			if !inserted && less(i, j) {
+				next = append(next, i)
+				inserted = true
+			}
+			next = append(next, j)
+		}
+		if !inserted {
+			next = append(next, i)
+		}
+		order = next
+	}
+
+	var out []any
+	for _, i := range order {
+		out = append(out, s[i])
+	}
+	return out
+}
+
+// re-implementation of maps.Copy.
+func maps_Copy(dst, src map[string]any) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
+
+// re-implementation of maps.Clone.
+func maps_Clone(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := map[string]any{}
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
 // wrapper around helm's lookup.
 func lookup(apiVersion, kind, namespace, name string) (map[string]any, bool) {
 	result := Lookup(apiVersion, kind, namespace, name)
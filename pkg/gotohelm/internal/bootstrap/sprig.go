@@ -74,6 +74,16 @@ func RegexFind(string, any) string {
 	panic("not implemented")
 }
 
+// +gotohelm:builtin=regexReplaceAll
+func RegexReplaceAll(string, any, string) string {
+	panic("not implemented")
+}
+
+// +gotohelm:builtin=atoi
+func Atoi(string) int {
+	panic("not implemented")
+}
+
 // +gotohelm:builtin=substr
 func Substr(int, int, any) string {
 	panic("not implemented")
@@ -83,3 +93,18 @@ func Substr(int, int, any) string {
 func ToString(any) string {
 	panic("not implemented")
 }
+
+// +gotohelm:builtin=tpl
+func Tpl(string, any) string {
+	panic("not implemented")
+}
+
+// +gotohelm:builtin=fromJson
+func FromJson(string) any {
+	panic("not implemented")
+}
+
+// +gotohelm:builtin=splitList
+func SplitList(string, string) []string {
+	panic("not implemented")
+}
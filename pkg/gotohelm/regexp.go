@@ -0,0 +1,167 @@
+package gotohelm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Compiled regular expressions (*regexp.Regexp) are represented in templates
+// by their pattern and their methods are lowered onto sprig's `regex*`
+// functions, which compile the pattern upon each call:
+//
+//	re := regexp.MustCompile(`^v(\d+)$`)
+//	re.MatchString(tag)
+//	// $re := "^v(\\d+)$"
+//	// (regexMatch $re $tag)
+//
+// sprig uses go's regexp package, so patterns share the same RE2 syntax.
+// Constant patterns are compiled upon transpilation such that invalid ones
+// are reported as [Unsupported] rather than failing the template.
+
+// checkPattern panics with an [Unsupported] if e, the pattern of a regular
+// expression, is a constant that doesn't compile.
+func checkPattern(ctx *CallContext, e ast.Expr) {
+	pattern, ok := constantString(ctx.TypesInfo, e)
+	if !ok {
+		return
+	}
+
+	if _, err := regexp.Compile(pattern); err != nil {
+		ctx.Unsupported(e, fmt.Sprintf("invalid regular expression: %v", err))
+	}
+}
+
+// findStringSubmatchCall transpiles re.FindStringSubmatch(s). sprig has no
+// equivalent, so each submatch is extracted by replacing the entirety of s
+// with a reference to its group. Doing so requires the number of groups of
+// re, which must be compiled from a constant pattern. NB: s is evaluated once
+// per group.
+func findStringSubmatchCall(ctx *CallContext, args []Node) Node {
+	recv := ctx.Call.Fun.(*ast.SelectorExpr).X
+	x, arg := ctx.Receiver, args[0]
+
+	pattern, ok := regexpPattern(ctx, recv)
+	if !ok {
+		ctx.Unsupported(recv, "FindStringSubmatch may only be called on regular expressions compiled from constant patterns, directly or through variables that are never reassigned")
+	}
+
+	// The lazy prefix finds the leftmost match just as FindStringSubmatch
+	// does. The pattern's own groups retain their numbering.
+	whole := NewLiteral(fmt.Sprintf(`(?s:.*?)(?:%s)(?s:.*)`, pattern))
+
+	matches := []Node{&BuiltInCall{FuncName: "regexFind", Arguments: []Node{x, arg}}}
+	for i := 1; i <= regexp.MustCompile(pattern).NumSubexp(); i++ {
+		matches = append(matches, &BuiltInCall{
+			FuncName:  "regexReplaceAll",
+			Arguments: []Node{whole, arg, NewLiteral(fmt.Sprintf("${%d}", i))},
+		})
+	}
+
+	return &BuiltInCall{
+		FuncName: "ternary",
+		Arguments: []Node{
+			&BuiltInCall{FuncName: "list", Arguments: matches},
+			&Nil{},
+			&BuiltInCall{FuncName: "regexMatch", Arguments: []Node{x, arg}},
+		},
+	}
+}
+
+// regexpPattern returns the constant pattern that x, a *regexp.Regexp, was
+// compiled from. x may be a call of regexp.MustCompile or a variable of this
+// package that is initialized by one and never reassigned.
+func regexpPattern(ctx *CallContext, x ast.Expr) (string, bool) {
+	x = ast.Unparen(x)
+
+	if call, ok := x.(*ast.CallExpr); ok {
+		fn, ok := typeutil.Callee(ctx.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "regexp" || fn.Name() != "MustCompile" {
+			return "", false
+		}
+		return constantString(ctx.TypesInfo, call.Args[0])
+	}
+
+	var ident *ast.Ident
+	switch x := x.(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	default:
+		return "", false
+	}
+
+	v, ok := ctx.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !ok {
+		return "", false
+	}
+
+	init := ctx.Initializer(v)
+	if init == nil {
+		return "", false
+	}
+	return regexpPattern(ctx, init)
+}
+
+// initializerOf returns the expression that v, a variable of this package,
+// is initialized to if it's never reassigned. Otherwise it returns nil.
+func (t *Transpiler) initializerOf(v *types.Var) ast.Expr {
+	var init ast.Expr
+	reassigned := false
+
+	assigns := func(lhs []ast.Expr, rhs []ast.Expr, define bool) {
+		for i, e := range lhs {
+			ident, ok := ast.Unparen(e).(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			if define && t.TypesInfo.Defs[ident] == v && len(lhs) == len(rhs) {
+				init = rhs[i]
+			} else if t.TypesInfo.ObjectOf(ident) == v {
+				reassigned = true
+			}
+		}
+	}
+
+	for _, file := range t.Package.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				assigns(n.Lhs, n.Rhs, n.Tok == token.DEFINE)
+			case *ast.ValueSpec:
+				var lhs []ast.Expr
+				for _, name := range n.Names {
+					lhs = append(lhs, name)
+				}
+				assigns(lhs, n.Values, true)
+			case *ast.UnaryExpr:
+				// Pointers to v may be used to reassign it.
+				if ident, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Op == token.AND && t.TypesInfo.ObjectOf(ident) == v {
+					reassigned = true
+				}
+			}
+			return true
+		})
+	}
+
+	if reassigned {
+		return nil
+	}
+	return init
+}
+
+// constantString returns the value of e if it's a constant string.
+func constantString(info *types.Info, e ast.Expr) (string, bool) {
+	tv := info.Types[e]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
@@ -8,6 +8,9 @@ import (
 	"go/parser"
 	"go/token"
 	"go/types"
+	"os"
+	"reflect"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 
@@ -51,7 +54,24 @@ func LoadPackages(cfg *packages.Config, patterns ...string) ([]*packages.Package
 		cfg.Overlay = map[string][]byte{}
 	}
 
+	// Rewrites are applied to the loaded packages and any helper packages
+	// they import as the latter will also be transpiled.
+	var toRewrite []*packages.Package
+	seen := map[*packages.Package]bool{}
 	for _, pkg := range pkgs {
+		for _, p := range append([]*packages.Package{pkg}, helperPackages(pkg)...) {
+			if !seen[p] {
+				seen[p] = true
+				toRewrite = append(toRewrite, p)
+			}
+		}
+	}
+
+	// originals holds the original source of any rewritten files. It's used
+	// to map positions within rewritten files back to the original source.
+	originals := map[string][]byte{}
+
+	for _, pkg := range toRewrite {
 		var errs []error
 		for i := range pkg.Errors {
 			e := pkg.Errors[i]
@@ -86,6 +106,16 @@ func LoadPackages(cfg *packages.Config, patterns ...string) ([]*packages.Package
 				return nil, err
 			}
 
+			if _, ok := originals[filename]; !ok {
+				original, ok := cfg.Overlay[filename]
+				if !ok {
+					if original, err = os.ReadFile(filename); err != nil {
+						return nil, errors.WithStack(err)
+					}
+				}
+				originals[filename] = original
+			}
+
 			cfg.Overlay[filename] = buf.Bytes()
 		}
 	}
@@ -95,6 +125,15 @@ func LoadPackages(cfg *packages.Config, patterns ...string) ([]*packages.Package
 		return nil, err
 	}
 
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, f := range pkg.Syntax {
+			file := pkg.Fset.File(f.Pos())
+			if original, ok := originals[file.Name()]; ok {
+				adjustPositions(file, original, cfg.Overlay[file.Name()])
+			}
+		}
+	})
+
 	for _, pkg := range pkgs {
 		var errs []error
 		for _, e := range pkg.Errors {
@@ -113,6 +152,142 @@ func LoadPackages(cfg *packages.Config, patterns ...string) ([]*packages.Package
 	return pkgs, nil
 }
 
+// adjustPositions adds alternative position information to file, akin to a
+// //line directive, such that positions within the rewritten source map back
+// to the original source when [token.FileSet.PositionFor] is called with
+// adjusted set to true.
+//
+// Lines are matched up by computing the longest common subsequence of the two
+// sources, ignoring whitespace and comments. Runs of rewritten lines that have
+// no match are attributed to the first non-blank original line that they
+// replaced with an unknown (0) column.
+func adjustPositions(file *token.File, original, rewritten []byte) {
+	originalLines := normalizeLines(original)
+	matches := matchLines(originalLines, normalizeLines(rewritten))
+
+	// Tracking for the most recently added line info to avoid adding
+	// redundant entries.
+	lastLine, lastColumn := 0, -1
+
+	prev := -1
+	for i := 0; i < len(matches) && i < file.LineCount(); i++ {
+		line, column := matches[i]+1, 1
+		if matches[i] == -1 {
+			line, column = replacedLine(originalLines, matches, prev, i)+1, 0
+		} else {
+			prev = matches[i]
+		}
+
+		// Consecutive lines with known columns are handled by the existing
+		// line info.
+		if column == lastColumn && column != 0 && line == lastLine+1 {
+			lastLine = line
+			continue
+		}
+
+		file.AddLineColumnInfo(file.Offset(file.LineStart(i+1)), file.Name(), line, column)
+		lastLine, lastColumn = line, column
+	}
+}
+
+// replacedLine returns the index of the first non-blank original line between
+// the matches surrounding the unmatched rewritten line i.
+func replacedLine(original []string, matches []int, prev, i int) int {
+	next := len(original)
+	for j := i + 1; j < len(matches); j++ {
+		if matches[j] != -1 {
+			next = matches[j]
+			break
+		}
+	}
+
+	for j := prev + 1; j < next; j++ {
+		if original[j] != "" {
+			return j
+		}
+	}
+
+	return prev + 1
+}
+
+// normalizeLines splits src into lines with all whitespace and trailing
+// comments removed. Both are subject to change when printing rewritten ASTs.
+func normalizeLines(src []byte) []string {
+	lines := strings.Split(string(src), "\n")
+	for i, line := range lines {
+		line, _, _ = strings.Cut(line, "//")
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return lines
+}
+
+// matchLines returns, for each element of b, the index of its matching
+// element in a or -1 as determined by the longest common subsequence of a and
+// b. Blank lines are never matched.
+func matchLines(a, b []string) []int {
+	eq := func(i, j int) bool {
+		return a[i] != "" && a[i] == b[j]
+	}
+
+	matches := make([]int, len(b))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	// Common prefixes and suffixes are trimmed for efficiency. Rewrites are
+	// generally localized.
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		if eq(start, start) {
+			matches[start] = start
+		}
+		start++
+	}
+
+	endA, endB := len(a), len(b)
+	for endA > start && endB > start && a[endA-1] == b[endB-1] {
+		endA--
+		endB--
+		if eq(endA, endB) {
+			matches[endB] = endA
+		}
+	}
+
+	n, m := endA-start, endB-start
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// a[start+i:endA] and b[start+j:endB].
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if eq(start+i, start+j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case eq(start+i, start+j):
+			matches[start+j] = start + i
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
+
 // typeToNode returns an [ast.Expr] representing the provided type.
 func typeToNode(pkg *packages.Package, typ types.Type) ast.Expr {
 	qualifier := func(p *types.Package) string {
@@ -146,9 +321,32 @@ func typeToNode(pkg *packages.Package, typ types.Type) ast.Expr {
 		panic(fmt.Sprintf("pkg errors (%s) with type (%s): %v", pkg.Name, s, err))
 	}
 
+	// The positions of expr are relative to a throwaway FileSet. Left as is,
+	// they'd point into arbitrary files of pkg.Fset.
+	clearPositions(expr)
+
 	return expr
 }
 
+// clearPositions sets all positions within node to [token.NoPos].
+func clearPositions(node ast.Node) {
+	posType := reflect.TypeOf(token.NoPos)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		v := reflect.ValueOf(n).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).Type() == posType {
+				v.Field(i).SetInt(int64(token.NoPos))
+			}
+		}
+		return true
+	})
+}
+
 // rewriteMultiValueReturns rewrites instances of multi-value returns into an
 // equivalent set of statements that utilizes a tuple followed by unpacking it.
 //
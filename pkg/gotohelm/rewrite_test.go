@@ -46,3 +46,42 @@ func TestLoadPackages(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchLines(t *testing.T) {
+	for _, tc := range []struct {
+		A, B     []string
+		Expected []int
+	}{
+		{
+			A:        []string{"a", "b", "c"},
+			B:        []string{"a", "b", "c"},
+			Expected: []int{0, 1, 2},
+		},
+		{
+			// Insertions.
+			A:        []string{"a", "b", "c"},
+			B:        []string{"a", "x", "y", "b", "c"},
+			Expected: []int{0, -1, -1, 1, 2},
+		},
+		{
+			// Removals, including blank lines.
+			A:        []string{"a", "", "b", "c", "d"},
+			B:        []string{"a", "c", "d"},
+			Expected: []int{0, 3, 4},
+		},
+		{
+			// Replacements.
+			A:        []string{"a", "b", "c", "d"},
+			B:        []string{"a", "x", "y", "z", "d"},
+			Expected: []int{0, -1, -1, -1, 3},
+		},
+		{
+			// Blank lines are never matched.
+			A:        []string{"a", "", "b"},
+			B:        []string{"", "a", "", "", "b"},
+			Expected: []int{-1, 0, -1, -1, 2},
+		},
+	} {
+		require.Equal(t, tc.Expected, matchLines(tc.A, tc.B), "%q -> %q", tc.A, tc.B)
+	}
+}
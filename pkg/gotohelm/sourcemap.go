@@ -0,0 +1,147 @@
+package gotohelm
+
+import (
+	"go/token"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// templateErrRE matches the position information of text/template errors.
+// e.g. template: redpanda/templates/secrets.go.tpl:1:2345: executing ...
+var templateErrRE = regexp.MustCompile(`template: ([^:\s]+):(\d+):(\d+)`)
+
+// SourceMap maps positions within a transpiled [File] back to the go source
+// that it was transpiled from. It is generated by [File.WriteWithSourceMap]
+// and may be serialized as JSON.
+type SourceMap struct {
+	// File is the name of the transpiled [File]. e.g. secrets.go.tpl
+	File     string    `json:"file"`
+	Mappings []Mapping `json:"mappings"`
+}
+
+// Mapping associates a position within a template with the go source of the
+// [Func] or statement that begins there. Template positions follow the
+// conventions of text/template's errors: Line is 1-based and Column is the
+// 0-based byte offset within the line.
+type Mapping struct {
+	Line         int    `json:"line"`
+	Column       int    `json:"column"`
+	SourceFile   string `json:"sourceFile"`
+	SourceLine   int    `json:"sourceLine"`
+	SourceColumn int    `json:"sourceColumn"`
+}
+
+// Source returns the go source position of this mapping.
+func (m Mapping) Source() token.Position {
+	return token.Position{Filename: m.SourceFile, Line: m.SourceLine, Column: m.SourceColumn}
+}
+
+// Lookup returns the go source position of the innermost [Func] or statement
+// that contains the given template position.
+func (m *SourceMap) Lookup(line, column int) (token.Position, bool) {
+	// Find the first mapping that comes after the given position. The one
+	// prior is the mapping that contains it.
+	i := sort.Search(len(m.Mappings), func(i int) bool {
+		mapping := m.Mappings[i]
+		return mapping.Line > line || (mapping.Line == line && mapping.Column > column)
+	})
+
+	if i == 0 {
+		return token.Position{}, false
+	}
+
+	return m.Mappings[i-1].Source(), true
+}
+
+// ResolveRenderError maps the template position reported by a helm rendering
+// error back to the go source it was transpiled from. Templates are matched
+// to [SourceMap]s by file name. Nested errors, as produced by include, are
+// resolved to the innermost position with a known [SourceMap].
+func ResolveRenderError(err error, maps []*SourceMap) (token.Position, bool) {
+	byFile := map[string]*SourceMap{}
+	for _, m := range maps {
+		byFile[m.File] = m
+	}
+
+	matches := templateErrRE.FindAllStringSubmatch(err.Error(), -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		m, ok := byFile[filepath.Base(matches[i][1])]
+		if !ok {
+			continue
+		}
+
+		line, _ := strconv.Atoi(matches[i][2])
+		column, _ := strconv.Atoi(matches[i][3])
+
+		if pos, ok := m.Lookup(line, column); ok {
+			return pos, true
+		}
+	}
+
+	return token.Position{}, false
+}
+
+// WriteWithSourceMap is equivalent to [File.Write] but additionally returns a
+// [SourceMap] of the written template.
+func (f *File) WriteWithSourceMap(w io.Writer) *SourceMap {
+	sw := &sourceMapWriter{
+		w:    w,
+		line: 1,
+		sm:   &SourceMap{File: f.Name},
+	}
+
+	f.Write(sw)
+
+	return sw.sm
+}
+
+// sourceMapWriter is an [io.Writer] that tracks the current line and column
+// of the template being written. [Node]s that are aware of their go source
+// call mark to record their position.
+type sourceMapWriter struct {
+	w      io.Writer
+	line   int
+	column int
+	sm     *SourceMap
+}
+
+func (w *sourceMapWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b == '\n' {
+			w.line++
+			w.column = 0
+		} else {
+			w.column++
+		}
+	}
+	return w.w.Write(p)
+}
+
+// mark records that the go source at pos begins at the current position of w,
+// if w is a [sourceMapWriter].
+func mark(w io.Writer, pos token.Position) {
+	sw, ok := w.(*sourceMapWriter)
+	if !ok || !pos.IsValid() {
+		return
+	}
+
+	mapping := Mapping{
+		Line:         sw.line,
+		Column:       sw.column,
+		SourceFile:   pos.Filename,
+		SourceLine:   pos.Line,
+		SourceColumn: pos.Column,
+	}
+
+	// Nested statements (e.g. blocks) may begin at the same position. The
+	// innermost takes precedence.
+	if n := len(sw.sm.Mappings); n > 0 && sw.sm.Mappings[n-1].Line == mapping.Line && sw.sm.Mappings[n-1].Column == mapping.Column {
+		sw.sm.Mappings[n-1] = mapping
+		return
+	}
+
+	sw.sm.Mappings = append(sw.sm.Mappings, mapping)
+}
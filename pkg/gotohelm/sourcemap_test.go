@@ -0,0 +1,167 @@
+package gotohelm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestSourceMaps(t *testing.T) {
+	td, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	root := filepath.Join(td, "src", "example")
+
+	pkgs, err := LoadPackages(&packages.Config{
+		Dir: root,
+		Env: append(
+			os.Environ(),
+			"GOPATH="+td,
+			"GO111MODULE=on",
+		),
+	}, "./labels")
+	require.NoError(t, err)
+
+	chart, err := Transpile(pkgs[0])
+	require.NoError(t, err)
+
+	tpl := template.New("labels")
+
+	funcs := sprig.FuncMap()
+	funcs["include"] = func(name string, data any) (string, error) {
+		var buf bytes.Buffer
+		err := tpl.ExecuteTemplate(&buf, name, data)
+		return buf.String(), err
+	}
+	funcs["lookup"] = func(...string) map[string]any { return nil }
+	funcs["toYaml"] = helmette.ToYaml
+	funcs["tpl"] = func(string, any) string { return "" }
+
+	tpl = tpl.Funcs(funcs)
+
+	var maps []*SourceMap
+	for _, f := range chart.Files {
+		var buf bytes.Buffer
+		maps = append(maps, f.WriteWithSourceMap(&buf))
+
+		// Source maps must not change the output of Write.
+		var plain bytes.Buffer
+		f.Write(&plain)
+		require.Equal(t, plain.String(), buf.String())
+
+		tpl, err = tpl.New(f.Name).Parse(buf.String())
+		require.NoError(t, err)
+	}
+
+	// Passing an int instead of a Dot will cause rendering to fail upon the
+	// first access of dot.Values.
+	err = tpl.ExecuteTemplate(&bytes.Buffer{}, "labels.FullLabels", map[string]any{"a": []any{1}})
+	require.ErrorContains(t, err, "template: labels.yaml:6:")
+
+	pos, ok := ResolveRenderError(err, maps)
+	require.True(t, ok)
+	require.Equal(t, filepath.Join(root, "labels", "labels.go"), pos.Filename)
+	require.Equal(t, 11, pos.Line)
+	require.Equal(t, 2, pos.Column)
+
+	// Positions before any mapping are unknown.
+	_, ok = maps[0].Lookup(1, 0)
+	require.False(t, ok)
+
+	// The define is attributed to the function declaration.
+	pos, ok = maps[0].Lookup(4, 0)
+	require.True(t, ok)
+	require.Equal(t, 10, pos.Line)
+}
+
+// TestSourceMapsOfRewrittenFiles asserts that statements of files modified by
+// the AST rewrites are mapped back to their lines within the original file,
+// despite the rewrites inserting and removing lines.
+func TestSourceMapsOfRewrittenFiles(t *testing.T) {
+	td, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	root := filepath.Join(td, "src", "example")
+
+	pkgs, err := LoadPackages(&packages.Config{
+		Dir: root,
+		Env: append(
+			os.Environ(),
+			"GOPATH="+td,
+			"GO111MODULE=on",
+		),
+	}, "./astrewrites")
+	require.NoError(t, err)
+
+	chart, err := Transpile(pkgs[0])
+	require.NoError(t, err)
+
+	var tpl string
+	var sm *SourceMap
+	for _, f := range chart.Files {
+		if f.Name == "astrewrites.yaml" {
+			var buf bytes.Buffer
+			sm = f.WriteWithSourceMap(&buf)
+			tpl = buf.String()
+		}
+	}
+	require.NotNil(t, sm)
+
+	lines := strings.Split(tpl, "\n")
+
+	// lookup returns the go source line of the n'th (0-based) template line
+	// equal to stmt.
+	lookup := func(stmt string, n int) int {
+		for i, line := range lines {
+			if line != stmt {
+				continue
+			}
+			if n > 0 {
+				n--
+				continue
+			}
+
+			pos, ok := sm.Lookup(i+1, len(line))
+			require.True(t, ok)
+			require.Equal(t, filepath.Join(root, "astrewrites", "astrewrites.go"), pos.Filename)
+			return pos.Line
+		}
+		t.Fatalf("%q not found in:\n%s", stmt, tpl)
+		return 0
+	}
+
+	for _, tc := range []struct {
+		Stmt string
+		N    int
+		Line int
+	}{
+		// x, y := m["1"] is rewritten into 3 statements, all of which are
+		// attributed to the original.
+		{Stmt: `{{- $y := $tmp_tuple_1.T2 -}}`, Line: 16},
+		{Stmt: `{{- $x := ($tmp_tuple_1.T1 | int) -}}`, Line: 16},
+		// Lines after inserted ones retain their original lines.
+		{Stmt: `{{- $_ = $x -}}`, Line: 17},
+		{Stmt: `{{- $_ = $y -}}`, Line: 18},
+		{Stmt: `{{- $a := $tmp_tuple_6.T1 -}}`, Line: 41},
+		{Stmt: `{{- $_ = $c -}}`, Line: 42},
+		// typeTest's blank lines are removed by the rewrite.
+		{Stmt: `{{- $ok := $tmp_tuple_9.T2 -}}`, Line: 64},
+		{Stmt: `{{- $_ = $ok -}}`, N: 1, Line: 65},
+		// Hoisted if-else chains are attributed to the first line of the
+		// chain.
+		{Stmt: `{{- $ok_4 := $tmp_tuple_14.T2 -}}`, Line: 73},
+		{Stmt: `{{- if $ok_1 -}}`, Line: 73},
+		// Declarations after all rewrites.
+		{Stmt: `{{- define "astrewrites.mvr3" -}}`, Line: 81},
+	} {
+		require.Equal(t, tc.Line, lookup(tc.Stmt, tc.N), "%s", tc.Stmt)
+	}
+}
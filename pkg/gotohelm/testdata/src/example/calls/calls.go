@@ -0,0 +1,25 @@
+package calls
+
+import (
+	"path"
+	"strings"
+
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+// Calls exercises functions that gotohelm doesn't support out of the box.
+// Their support is registered by this package's TestSpec via
+// TranspileOptions.Calls, as a chart repository would for third party
+// packages.
+func Calls(dot *helmette.Dot) map[string]any {
+	file, _ := dot.Values["file"].(string)
+
+	return map[string]any{
+		"base": path.Base(file),
+		"dir":  path.Dir(file),
+		"ext":  path.Ext(file),
+		"join": path.Join("/etc", "redpanda", "..", "secrets"),
+		// Registered calls may replace the default support of functions.
+		"prefixed": strings.HasPrefix(file, "/etc/"),
+	}
+}
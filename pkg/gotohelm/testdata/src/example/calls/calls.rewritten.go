@@ -0,0 +1,27 @@
+//go:build rewrites
+package calls
+
+import (
+	"path"
+	"strings"
+
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+// Calls exercises functions that gotohelm doesn't support out of the box.
+// Their support is registered by this package's TestSpec via
+// TranspileOptions.Calls, as a chart repository would for third party
+// packages.
+func Calls(dot *helmette.Dot) map[string]any {
+	tmp_tuple_1 := helmette.Compact2(helmette.TypeTest[string](dot.Values["file"]))
+	file := tmp_tuple_1.T1
+
+	return map[string]any{
+		"base": path.Base(file),
+		"dir":  path.Dir(file),
+		"ext":  path.Ext(file),
+		"join": path.Join("/etc", "redpanda", "..", "secrets"),
+		// Registered calls may replace the default support of functions.
+		"prefixed": strings.HasPrefix(file, "/etc/"),
+	}
+}
@@ -0,0 +1,112 @@
+package closures
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+type Config struct {
+	Name     string
+	Replicas int
+}
+
+func Closures(dot *helmette.Dot) map[string]any {
+	prefix := "item-"
+	name := func(i int) string {
+		return fmt.Sprintf("%s%d", prefix, i)
+	}
+
+	// Maps are captured by reference. Mutations made by the closure are
+	// visible to the enclosing function.
+	counts := map[string]int{}
+	incr := func(key string) {
+		counts[key] = counts[key] + 1
+	}
+	incr("a")
+	incr("a")
+	incr("b")
+
+	// As are structs, pointer or otherwise.
+	ptr := &Config{Name: "ptr", Replicas: 1}
+	value := Config{Name: "value", Replicas: 1}
+	scale := func(by int) {
+		ptr.Replicas = ptr.Replicas * by
+		value.Replicas = value.Replicas * by
+	}
+	scale(3)
+
+	threshold := 2
+	if t, ok := helmette.AsIntegral[int](dot.Values["threshold"]); ok {
+		threshold = t
+	}
+
+	return map[string]any{
+		"name":      name(1),
+		"counts":    counts,
+		"ptr":       ptr,
+		"value":     value,
+		"doubled":   mapInts([]int{1, 2, 3}, func(i int) int { return i * 2 }),
+		"squared":   mapInts([]int{1, 2, 3}, square),
+		"filtered":  filter([]int{1, 2, 3, 4}, func(i int) bool { return i > threshold }),
+		"adder":     adder(5)(10),
+		"immediate": func() string { return prefix + "immediate" }(),
+		"nested":    nested(prefix),
+		"unnamed":   apply(func(_ int, s string) string { return s }),
+		"ignored":   mapInts([]int{1, 2}, func(int) int { return 7 }),
+		"greeted":   []string{greeters["a"](), greeters["b"]()},
+	}
+}
+
+func square(i int) int {
+	return i * i
+}
+
+func mapInts(in []int, fn func(int) int) []int {
+	out := []int{}
+	for _, i := range in {
+		out = append(out, fn(i))
+	}
+	return out
+}
+
+func filter(in []int, keep func(int) bool) []int {
+	out := []int{}
+	for _, i := range in {
+		if keep(i) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func apply(fn func(int, string) string) string {
+	return fn(1, "second")
+}
+
+// adder returns a closure, which survives being returned from a function.
+func adder(x int) func(int) int {
+	return func(y int) int {
+		return x + y
+	}
+}
+
+// nested closures capture variables from all enclosing functions.
+func nested(prefix string) []string {
+	suffix := "-suffix"
+	outer := func(name string) func() string {
+		return func() string {
+			return prefix + name + suffix
+		}
+	}
+	return []string{outer("a")(), outer("b")()}
+}
+
+// greeters are function literals of a package level variable.
+var greeters = map[string]func() string{
+	"a": func() string { return "A" },
+	"b": func() string {
+		suffix := "!"
+		return func() string { return "B" + suffix }()
+	},
+}
@@ -0,0 +1,76 @@
+package failures
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+type Config struct {
+	Name     string
+	Replicas int
+}
+
+func (c *Config) Validate() {
+	if c.Replicas < 1 {
+		panic(fmt.Sprintf("%s: replicas must be at least 1. Got: %d", c.Name, c.Replicas))
+	}
+}
+
+func (c Config) Require() {
+	helmette.Required("name is required", c.Name)
+}
+
+type Stack[T any] struct {
+	Items []T
+}
+
+func (s *Stack[T]) Peek() T {
+	if len(s.Items) == 0 {
+		panic("stack is empty")
+	}
+	return s.Items[len(s.Items)-1]
+}
+
+func first[T any](xs []T) T {
+	if len(xs) == 0 {
+		panic("no elements")
+	}
+	return xs[0]
+}
+
+func Failures(dot *helmette.Dot) map[string]any {
+	mode := dot.Values["mode"]
+
+	switch mode {
+	case "panic":
+		panic("panicked")
+	case "fail":
+		helmette.Fail("failed")
+	case "required":
+		helmette.Required("value is required", dot.Values["value"])
+	case "method":
+		cfg := &Config{Name: "failures"}
+		cfg.Validate()
+	case "value-method":
+		cfg := Config{Replicas: 1}
+		cfg.Require()
+	case "closure":
+		// Failures within function literals are not annotated.
+		validate := func(n int) {
+			if n < 1 {
+				panic(fmt.Sprintf("n must be positive. Got: %d", n))
+			}
+		}
+		validate(0)
+	case "generic":
+		first([]string{})
+	case "generic-method":
+		s := &Stack[int]{}
+		s.Peek()
+	}
+
+	return map[string]any{
+		"mode": mode,
+	}
+}
@@ -12,6 +12,10 @@ func FlowControl(dot *helmette.Dot) map[string]any {
 		"sliceRanges":    sliceRanges(dot),
 		"mapRanges":      mapRanges(dot),
 		"intBinaryExprs": intBinaryExprs(),
+		"switches":       switches(dot),
+		"forLoops":       forLoops(dot),
+		"intRanges":      intRanges(dot),
+		"stringRanges":   stringRanges(),
 	}
 }
 
@@ -117,3 +121,234 @@ func intBinaryExprs() []int {
 		x * y,
 	}
 }
+
+func switches(dot *helmette.Dot) []string {
+	tmp_tuple_4 := helmette.Compact2(helmette.AsIntegral[int](dot.Values["oneToFour"]))
+	oneToFour := tmp_tuple_4.T1
+
+	var out []string
+
+	// Expression switch with multi-value cases and a default.
+	switch oneToFour {
+	case 1:
+		out = append(out, "one")
+	case 2, 3:
+		out = append(out, "two or three")
+	default:
+		out = append(out, "four")
+	}
+
+	// Tagless switch.
+	switch {
+	case oneToFour < 2:
+		out = append(out, "less than two")
+	case oneToFour%2 == 0:
+		out = append(out, "even")
+	}
+
+	// Init statements, fallthrough, and a default that's not the final case.
+	switch doubled := oneToFour * 2; doubled {
+	case 2:
+		out = append(out, "doubled is 2")
+		fallthrough
+	case 4:
+		out = append(out, "doubled is 4 or fell through")
+		fallthrough
+	default:
+		out = append(out, "default or fell through")
+	case 8:
+		out = append(out, "doubled is 8")
+		break
+	}
+
+	// Tagless switch with an init statement and no matching case.
+	switch b := dot.Values["boolean"]; {
+	case b == nil:
+		out = append(out, "boolean is nil")
+	}
+
+	// Switches on strings and within loops.
+	for _, s := range []string{"a", "b", "c", "d"} {
+		switch s {
+		case "a":
+			continue
+		case "b", "c":
+			for _, i := range []int{0, 1, 2} {
+				if i == 1 {
+					break
+				}
+				out = append(out, s)
+			}
+		default:
+			out = append(out, "not a, b, or c")
+		}
+	}
+
+	// Default only.
+	switch {
+	default:
+		out = append(out, "default only")
+	}
+
+	return out
+}
+
+func forLoops(dot *helmette.Dot) []any {
+	tmp_tuple_5 := helmette.Compact2(helmette.AsIntegral[int](dot.Values["oneToFour"]))
+	oneToFour := tmp_tuple_5.T1
+
+	// While style.
+	doubled := 1
+	for doubled < 100 {
+		doubled = doubled * (oneToFour + 1)
+	}
+
+	// Infinite loops with a break.
+	countdown := oneToFour
+	for {
+		if countdown == 0 {
+			break
+		}
+		countdown--
+	}
+
+	// Continue statements still execute the post statement.
+	var odds []int
+	for i := 0; i < 10; i++ {
+		if i%2 == 0 {
+			continue
+		}
+		odds = append(odds, i)
+	}
+
+	// Counters modified by the loop's body.
+	var skipped []int
+	for i := 0; i < 10; i++ {
+		if i == oneToFour {
+			i += 3
+		}
+		skipped = append(skipped, i)
+	}
+
+	// Inclusive bounds and multiple variables.
+	var pairs []int
+	for i, j := 0, oneToFour; i <= j; i, j = i+1, j-1 {
+		pairs = append(pairs, i*10+j)
+	}
+
+	// Post statements that don't step the counter.
+	var stepped []int
+	step := 0
+	for i := 0; i < oneToFour; step++ {
+		if step >= 3 {
+			break
+		}
+		stepped = append(stepped, i+step)
+	}
+
+	// Bounds are the current values of variables, not their initializers.
+	bound := 0
+	bound = oneToFour + 1
+	counted := 0
+	for i := 0; i < bound; i++ {
+		counted++
+	}
+
+	lo, hi := 1, oneToFour*2
+	var spanned []int
+	for i := lo; i < hi; i++ {
+		spanned = append(spanned, i)
+	}
+
+	return []any{
+		doubled,
+		countdown,
+		odds,
+		skipped,
+		pairs,
+		stepped,
+		counted,
+		spanned,
+		retry(oneToFour),
+		backoff(oneToFour),
+	}
+}
+
+// retry returns from within nested loops.
+func retry(attempts int) string {
+	for attempt := 1; attempt <= attempts; attempt++ {
+		for _, s := range []string{"a", "b"} {
+			if attempt == 3 && s == "b" {
+				return "succeeded on attempt 3"
+			}
+		}
+	}
+	return "gave up"
+}
+
+// backoff returns from within a while style loop.
+func backoff(max int) int {
+	delay := 1
+	for {
+		if delay*2 > max*3 {
+			return delay
+		}
+		delay = delay * 2
+	}
+}
+
+func intRanges(dot *helmette.Dot) []any {
+	tmp_tuple_6 := helmette.Compact2(helmette.AsIntegral[int](dot.Values["oneToFour"]))
+	oneToFour := tmp_tuple_6.T1
+
+	var indexes []int
+	for i := range oneToFour {
+		indexes = append(indexes, i)
+	}
+
+	count := 0
+	for range 3 {
+		count++
+	}
+
+	var n int64 = 2
+	for i := range n {
+		count = count + int(i)*10
+	}
+
+	return []any{indexes, count}
+}
+
+func stringRanges() []any {
+	var offsets []int
+	var chars []rune
+	for i, r := range "añ€😀b" {
+		offsets = append(offsets, i)
+		chars = append(chars, r)
+	}
+
+	lower := 0
+	for _, r := range "Hello, World" {
+		if r >= 'a' && r <= 'z' {
+			lower++
+		}
+	}
+
+	length := 0
+	for range "añ€" {
+		length++
+	}
+
+	var keys []int
+	for i := range "ab" {
+		keys = append(keys, i)
+	}
+
+	// Invalid UTF-8, including truncated sequences, yields U+FFFD per byte.
+	var invalid []rune
+	for _, r := range "a\xffb\xe2\x82c" {
+		invalid = append(invalid, r)
+	}
+
+	return []any{offsets, chars, lower, length, keys, invalid}
+}
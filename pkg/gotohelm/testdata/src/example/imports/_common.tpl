@@ -0,0 +1,60 @@
+{{- /* Generated from "example.com/example/imports/helpers" */ -}}
+
+{{- define "common.DefaultAnnotations" -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (dict "example.com/owner" "imports" )) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "common.Meta.Labels" -}}
+{{- $m := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (dict "app.kubernetes.io/name" $m.Name "app.kubernetes.io/instance" $m.Namespace )) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "common.Named.Name" -}}
+{{- $x := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- if (eq (index $x 0) "imports.Widget") -}}
+{{- (dict "r" (get (fromJson (include "imports.Widget.Name" (dict "a" (list (index $x 1)) ))) "r")) | toJson -}}
+{{- break -}}
+{{- else -}}
+{{- $_ := (fail (printf "%s does not implement common.Named" (index $x 0))) -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "common.Describe" -}}
+{{- $n := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (printf "%s%s" "named " (get (fromJson (include "common.Named.Name" (dict "a" (list $n) ))) "r"))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "common.MetaFor" -}}
+{{- $dot := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (mustMergeOverwrite (dict "Name" "" "Namespace" "" ) (dict "Name" (get (fromJson (include "common.Fullname" (dict "a" (list $dot) ))) "r") "Namespace" $dot.Release.Namespace ))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "common.Fullname" -}}
+{{- $dot := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- $tmp_tuple_1 := (get (fromJson (include "_shims.compact" (dict "a" (list (get (fromJson (include "_shims.dicttest" (dict "a" (list $dot.Values "fullnameOverride" (coalesce nil)) ))) "r")) ))) "r") -}}
+{{- $ok_2 := $tmp_tuple_1.T2 -}}
+{{- $override_1 := $tmp_tuple_1.T1 -}}
+{{- if $ok_2 -}}
+{{- (dict "r" (get (fromJson (include "naming.Truncate" (dict "a" (list (get (fromJson (include "_shims.typeassertion" (dict "a" (list "string" $override_1) ))) "r")) ))) "r")) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" (get (fromJson (include "naming.Truncate" (dict "a" (list (printf "%s-%s" $dot.Release.Name $dot.Chart.Name)) ))) "r")) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
@@ -0,0 +1,10 @@
+{{- /* Generated from "example.com/example/imports/naming" */ -}}
+
+{{- define "naming.Truncate" -}}
+{{- $name := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (trimSuffix "-" (trunc (63 | int) $name))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
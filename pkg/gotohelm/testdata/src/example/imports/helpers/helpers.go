@@ -0,0 +1,48 @@
+// +gotohelm:namespace=common
+package helpers
+
+import (
+	"fmt"
+
+	"example.com/example/imports/naming"
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+// DefaultAnnotations are applied to every resource.
+var DefaultAnnotations = map[string]string{"example.com/owner": "imports"}
+
+type Meta struct {
+	Name      string
+	Namespace string
+}
+
+func (m *Meta) Labels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     m.Name,
+		"app.kubernetes.io/instance": m.Namespace,
+	}
+}
+
+// Named is implemented by types of the packages that import helpers.
+type Named interface {
+	Name() string
+}
+
+func Describe(n Named) string {
+	return "named " + n.Name()
+}
+
+func MetaFor(dot *helmette.Dot) Meta {
+	return Meta{
+		Name:      Fullname(dot),
+		Namespace: dot.Release.Namespace,
+	}
+}
+
+func Fullname(dot *helmette.Dot) string {
+	// Exercises AST rewrites within a helper package.
+	if override, ok := dot.Values["fullnameOverride"]; ok {
+		return naming.Truncate(override.(string))
+	}
+	return naming.Truncate(fmt.Sprintf("%s-%s", dot.Release.Name, dot.Chart.Name))
+}
@@ -0,0 +1,53 @@
+//go:build rewrites
+// +gotohelm:namespace=common
+package helpers
+
+import (
+	"fmt"
+
+	"example.com/example/imports/naming"
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+// DefaultAnnotations are applied to every resource.
+var DefaultAnnotations = map[string]string{"example.com/owner": "imports"}
+
+type Meta struct {
+	Name      string
+	Namespace string
+}
+
+func (m *Meta) Labels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     m.Name,
+		"app.kubernetes.io/instance": m.Namespace,
+	}
+}
+
+// Named is implemented by types of the packages that import helpers.
+type Named interface {
+	Name() string
+}
+
+func Describe(n Named) string {
+	return "named " + n.Name()
+}
+
+func MetaFor(dot *helmette.Dot) Meta {
+	return Meta{
+		Name:      Fullname(dot),
+		Namespace: dot.Release.Namespace,
+	}
+}
+
+func Fullname(dot *helmette.Dot) string {
+	tmp_tuple_1 :=
+		// Exercises AST rewrites within a helper package.
+		helmette.Compact2(helmette.DictTest[string, any](dot.Values, "fullnameOverride"))
+	ok_2 := tmp_tuple_1.T2
+	override_1 := tmp_tuple_1.T1
+	if ok_2 {
+		return naming.Truncate(override_1.(string))
+	}
+	return naming.Truncate(fmt.Sprintf("%s-%s", dot.Release.Name, dot.Chart.Name))
+}
@@ -0,0 +1,26 @@
+package imports
+
+import (
+	"example.com/example/imports/helpers"
+	"example.com/example/imports/naming"
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+type Widget struct{}
+
+func (Widget) Name() string {
+	return "widget"
+}
+
+func Imports(dot *helmette.Dot) map[string]any {
+	meta := helpers.MetaFor(dot)
+
+	return map[string]any{
+		"fullname":    helpers.Fullname(dot),
+		"meta":        meta,
+		"labels":      meta.Labels(),
+		"annotations": helpers.DefaultAnnotations,
+		"described":   helpers.Describe(Widget{}),
+		"truncate":    naming.Truncate("a-very-long-name-that-is-going-to-be-truncated-to-exactly-63-chars-"),
+	}
+}
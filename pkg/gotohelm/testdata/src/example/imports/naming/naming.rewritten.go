@@ -0,0 +1,13 @@
+//go:build rewrites
+// Package naming is a helper package that's transitively imported by the
+// imports chart via helpers. It doesn't specify a namespace so it defaults to
+// its package name.
+package naming
+
+import "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+
+// Truncate mimics the common helm pattern of truncating names to 63
+// characters.
+func Truncate(name string) string {
+	return helmette.TrimSuffix("-", helmette.Trunc(63, name))
+}
@@ -75,6 +75,63 @@
 {{- end -}}
 {{- end -}}
 
+{{- define "_shims.bitwiseor" -}}
+{{- $a := (index .a 0) -}}
+{{- $b := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- $negative := (or (lt $a (0 | int64)) (lt $b (0 | int64))) -}}
+{{- if (lt $a (0 | int64)) -}}
+{{- $a = ((add ((add $a (9223372036854775807 | int)) | int64) (1 | int64)) | int64) -}}
+{{- end -}}
+{{- if (lt $b (0 | int64)) -}}
+{{- $b = ((add ((add $b (9223372036854775807 | int)) | int64) (1 | int64)) | int64) -}}
+{{- end -}}
+{{- $result := ((0 | int64) | int64) -}}
+{{- $bit := ((1 | int64) | int64) -}}
+{{- $bits := (63 | int) -}}
+{{- range $_, $i := untilStep ((0 | int)|int) ($bits|int) (1|int) -}}
+{{- if (or (eq ((mod (((div $a $bit) | int64)) (2 | int64)) | int64) (1 | int64)) (eq ((mod (((div $b $bit) | int64)) (2 | int64)) | int64) (1 | int64))) -}}
+{{- $result = ((add $result $bit) | int64) -}}
+{{- end -}}
+{{- if (lt $i ((sub $bits (1 | int)) | int)) -}}
+{{- $bit = ((mul $bit (2 | int64)) | int64) -}}
+{{- end -}}
+{{- end -}}
+{{- if $negative -}}
+{{- (dict "r" ((add $result (-9223372036854775808 | int)) | int64)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" $result) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.runes" -}}
+{{- $s := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- $lead := (list (0 | int) (0 | int) (192 | int) (224 | int) (240 | int)) -}}
+{{- $limit := (list (0 | int) (128 | int) (224 | int) (240 | int) (248 | int)) -}}
+{{- $out := (coalesce nil) -}}
+{{- $offset := (0 | int) -}}
+{{- range $_, $char := (splitList "" $s) -}}
+{{- $n := (len $char) -}}
+{{- $valid := (and (ge ((index $char (0 | int)) | int) (index $lead $n)) (lt ((index $char (0 | int)) | int) (index $limit $n))) -}}
+{{- $r := ((sub ((index $char (0 | int)) | int) (index $lead $n)) | int) -}}
+{{- range $_, $i := untilStep ((1 | int)|int) ($n|int) (1|int) -}}
+{{- $valid = (and (and $valid (ge ((index $char $i) | int) (128 | int))) (lt ((index $char $i) | int) (192 | int))) -}}
+{{- $r = ((sub ((add ((mul $r (64 | int)) | int) ((index $char $i) | int)) | int) (128 | int)) | int) -}}
+{{- end -}}
+{{- if (not $valid) -}}
+{{- $r = (65533 | int) -}}
+{{- end -}}
+{{- $out = (concat (default (list ) $out) (list (list $offset $r))) -}}
+{{- $offset = ((add $offset $n) | int) -}}
+{{- end -}}
+{{- (dict "r" $out) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
 {{- define "_shims.ptr_Deref" -}}
 {{- $ptr := (index .a 0) -}}
 {{- $def := (index .a 1) -}}
@@ -96,7 +153,329 @@
 {{- (dict "r" true) | toJson -}}
 {{- break -}}
 {{- end -}}
-{{- (dict "r" (eq $a $b)) | toJson -}}
+{{- (dict "r" (eq (toJson $a) (toJson $b))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.strings_Index" -}}
+{{- $s := (index .a 0) -}}
+{{- $substr := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- if (eq $substr "") -}}
+{{- (dict "r" (0 | int)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $parts := (splitList $substr $s) -}}
+{{- if (eq (len $parts) (1 | int)) -}}
+{{- (dict "r" -1) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" (len (index $parts (0 | int)))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.strings_Count" -}}
+{{- $s := (index .a 0) -}}
+{{- $substr := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- if (eq $substr "") -}}
+{{- (dict "r" ((add (len (splitList "" $s)) (1 | int)) | int)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" ((sub (len (splitList $substr $s)) (1 | int)) | int)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.fmt_Sprintf" -}}
+{{- $format := (index .a 0) -}}
+{{- $a := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- $n := (len $a) -}}
+{{- if (eq $n (0 | int)) -}}
+{{- (dict "r" (printf $format)) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (1 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (2 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (3 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (4 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (5 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)) (index $a (4 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (6 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)) (index $a (4 | int)) (index $a (5 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (7 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)) (index $a (4 | int)) (index $a (5 | int)) (index $a (6 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (8 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)) (index $a (4 | int)) (index $a (5 | int)) (index $a (6 | int)) (index $a (7 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (9 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)) (index $a (4 | int)) (index $a (5 | int)) (index $a (6 | int)) (index $a (7 | int)) (index $a (8 | int)))) | toJson -}}
+{{- break -}}
+{{- else -}}{{- if (eq $n (10 | int)) -}}
+{{- (dict "r" (printf $format (index $a (0 | int)) (index $a (1 | int)) (index $a (2 | int)) (index $a (3 | int)) (index $a (4 | int)) (index $a (5 | int)) (index $a (6 | int)) (index $a (7 | int)) (index $a (8 | int)) (index $a (9 | int)))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- $_ := (fail (printf "fmt.Sprintf: spreads of more than 10 arguments are not supported. Got: %d" $n)) -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.strconv_FormatInt" -}}
+{{- $i := (index .a 0) -}}
+{{- $base := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- if (or (lt $base (2 | int)) (gt $base (36 | int))) -}}
+{{- $_ := (fail "strconv: illegal AppendInt/FormatInt base") -}}
+{{- end -}}
+{{- if (eq $i (0 | int64)) -}}
+{{- (dict "r" "0") | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $digits := "0123456789abcdefghijklmnopqrstuvwxyz" -}}
+{{- $sign := "-" -}}
+{{- if (gt $i (0 | int64)) -}}
+{{- $sign = "" -}}
+{{- $i = ((mul $i -1) | int64) -}}
+{{- end -}}
+{{- $out := "" -}}
+{{- range $_, $tmp_iteration_1 := until (10001|int) -}}
+{{- if (not (lt $i (0 | int64))) -}}
+{{- break -}}
+{{- end -}}
+{{- if (eq $tmp_iteration_1 10000) -}}
+{{- $_ := (fail "for loop at bootstrap.go:250 exceeded the maximum of 10000 iterations") -}}
+{{- end -}}
+{{- $digit := (((mul (((mod $i ($base | int64)) | int64)) -1) | int64) | int) -}}
+{{- $out = (printf "%s%s" (substr $digit ((add $digit (1 | int)) | int) $digits) $out) -}}
+{{- $i = ((div $i ($base | int64)) | int64) -}}
+{{- end -}}
+{{- (dict "r" (printf "%s%s" $sign $out)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.strconv_Atoi" -}}
+{{- $s := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- if (not (regexMatch `^[+-]?[0-9]+$` $s)) -}}
+{{- (dict "r" (list (0 | int) (printf "strconv.Atoi: parsing %q: invalid syntax" $s))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $n := (atoi $s) -}}
+{{- $digits := (regexReplaceAll `^[+-]?0*` $s "") -}}
+{{- if (or ((and (eq $n (9223372036854775807 | int)) (ne $digits "9223372036854775807"))) ((and (eq $n (-9223372036854775808 | int)) (ne $digits "9223372036854775808")))) -}}
+{{- (dict "r" (list $n (printf "strconv.Atoi: parsing %q: value out of range" $s))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" (list $n (coalesce nil))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.strconv_ParseBool" -}}
+{{- $s := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- if (or (or (or (or (or (eq $s "1") (eq $s "t")) (eq $s "T")) (eq $s "TRUE")) (eq $s "true")) (eq $s "True")) -}}
+{{- (dict "r" (list true (coalesce nil))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- if (or (or (or (or (or (eq $s "0") (eq $s "f")) (eq $s "F")) (eq $s "FALSE")) (eq $s "false")) (eq $s "False")) -}}
+{{- (dict "r" (list false (coalesce nil))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" (list false (printf "strconv.ParseBool: parsing %q: invalid syntax" $s))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slices_Index" -}}
+{{- $s := (index .a 0) -}}
+{{- $v := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- $idx := -1 -}}
+{{- range $i, $e := $s -}}
+{{- if (eq (toJson $e) (toJson $v)) -}}
+{{- $idx = $i -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+{{- (dict "r" $idx) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slices_Contains" -}}
+{{- $s := (index .a 0) -}}
+{{- $v := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (ne ((get (fromJson (include "_shims.slices_Index" (dict "a" (list $s $v) ))) "r") | int) -1)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slices_Compact" -}}
+{{- $s := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- if (empty $s) -}}
+{{- (dict "r" $s) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $out := (coalesce nil) -}}
+{{- range $i, $e := $s -}}
+{{- if (or (eq $i (0 | int)) (ne (toJson $e) (toJson (index $s ((sub $i (1 | int)) | int))))) -}}
+{{- $out = (concat (default (list ) $out) (list $e)) -}}
+{{- end -}}
+{{- end -}}
+{{- (dict "r" $out) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slices_Reverse" -}}
+{{- $s := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- if (empty $s) -}}
+{{- (dict "r" $s) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $out := (coalesce nil) -}}
+{{- range $i, $_ := $s -}}
+{{- $out = (concat (default (list ) $out) (list (index $s ((sub ((sub (len $s) (1 | int)) | int) $i) | int)))) -}}
+{{- end -}}
+{{- (dict "r" $out) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slice_Set" -}}
+{{- $s := (index .a 0) -}}
+{{- $i := (index .a 1) -}}
+{{- $v := (index .a 2) -}}
+{{- range $_ := (list 1) -}}
+{{- if (or (lt $i (0 | int)) (ge $i (len $s))) -}}
+{{- $_ := (fail (printf "runtime error: index out of range [%d] with length %d" $i (len $s))) -}}
+{{- end -}}
+{{- $out := (coalesce nil) -}}
+{{- range $j, $e := $s -}}
+{{- if (eq $j $i) -}}
+{{- $out = (concat (default (list ) $out) (list $v)) -}}
+{{- else -}}
+{{- $out = (concat (default (list ) $out) (list $e)) -}}
+{{- end -}}
+{{- end -}}
+{{- (dict "r" $out) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slices_Sort" -}}
+{{- $s := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (get (fromJson (include "_shims.sortBy" (dict "a" (list $s (list "_shims.slices_Sort.func1" (list $s))) ))) "r")) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.slices_Sort.func1" -}}
+{{- $s := (index .a 0) -}}
+{{- $i := (index .a 1) -}}
+{{- $j := (index .a 2) -}}
+{{- range $_ := (list 1) -}}
+{{- if (typeIs "string" (index $s $i)) -}}
+{{- (dict "r" (lt (toString (index $s $i)) (toString (index $s $j)))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- (dict "r" (lt (float64 (index $s $i)) (float64 (index $s $j)))) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.sort_Slice" -}}
+{{- $s := (index .a 0) -}}
+{{- $less := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- (dict "r" (get (fromJson (include "_shims.sortBy" (dict "a" (list $s $less) ))) "r")) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.sortBy" -}}
+{{- $s := (index .a 0) -}}
+{{- $less := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- if (empty $s) -}}
+{{- (dict "r" $s) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $order := (coalesce nil) -}}
+{{- range $i, $_ := $s -}}
+{{- $next := (coalesce nil) -}}
+{{- $inserted := false -}}
+{{- range $_, $j := $order -}}
+{{- if (and (not $inserted) (get (fromJson (include (first $less) (dict "a" (concat (last $less) (list $i $j)) ))) "r")) -}}
+{{- $next = (concat (default (list ) $next) (list $i)) -}}
+{{- $inserted = true -}}
+{{- end -}}
+{{- $next = (concat (default (list ) $next) (list $j)) -}}
+{{- end -}}
+{{- if (not $inserted) -}}
+{{- $next = (concat (default (list ) $next) (list $i)) -}}
+{{- end -}}
+{{- $order = $next -}}
+{{- end -}}
+{{- $out := (coalesce nil) -}}
+{{- range $_, $i := $order -}}
+{{- $out = (concat (default (list ) $out) (list (index $s $i))) -}}
+{{- end -}}
+{{- (dict "r" $out) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.maps_Copy" -}}
+{{- $dst := (index .a 0) -}}
+{{- $src := (index .a 1) -}}
+{{- range $_ := (list 1) -}}
+{{- range $k, $v := $src -}}
+{{- $_ := (set $dst $k $v) -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "_shims.maps_Clone" -}}
+{{- $m := (index .a 0) -}}
+{{- range $_ := (list 1) -}}
+{{- if (eq $m (coalesce nil)) -}}
+{{- (dict "r" (coalesce nil)) | toJson -}}
+{{- break -}}
+{{- end -}}
+{{- $out := (dict ) -}}
+{{- range $k, $v := $m -}}
+{{- $_ := (set $out $k $v) -}}
+{{- end -}}
+{{- (dict "r" $out) | toJson -}}
 {{- break -}}
 {{- end -}}
 {{- end -}}
@@ -144,7 +523,7 @@
 {{- (dict "r" (list $value true)) | toJson -}}
 {{- break -}}
 {{- end -}}
-{{- if (and (typeIs "float64" $value) (eq (floor $value) $value)) -}}
+{{- if (and (typeIs "float64" $value) (eq (toJson (floor $value)) (toJson $value))) -}}
 {{- (dict "r" (list $value true)) | toJson -}}
 {{- break -}}
 {{- end -}}
@@ -169,7 +548,7 @@
 {{- $reprStr := (toString $repr) -}}
 {{- $unit := (regexFind "(k|m|M|G|T|P|Ki|Mi|Gi|Ti|Pi)$" $repr) -}}
 {{- $numeric := (float64 (substr (0 | int) ((sub ((get (fromJson (include "_shims.len" (dict "a" (list $reprStr) ))) "r") | int) ((get (fromJson (include "_shims.len" (dict "a" (list $unit) ))) "r") | int)) | int) $reprStr)) -}}
-{{- $tmp_tuple_1 := (get (fromJson (include "_shims.compact" (dict "a" (list (get (fromJson (include "_shims.dicttest" (dict "a" (list (dict "" 1.0 "m" 0.001 "k" (1000 | int) "M" (1000000 | int) "G" (1000000000 | int) "T" (1000000000000 | int) "P" (1000000000000000 | int) "Ki" (1024 | int) "Mi" (1048576 | int) "Gi" (1073741824 | int) "Ti" (1099511627776 | int) "Pi" (1125899906842624 | int) ) $unit (coalesce nil)) ))) "r")) ))) "r") -}}
+{{- $tmp_tuple_1 := (get (fromJson (include "_shims.compact" (dict "a" (list (get (fromJson (include "_shims.dicttest" (dict "a" (list (dict "" 1.0 "m" 0.001 "k" (1000 | int) "M" (1000000 | int) "G" (1000000000 | int) "T" (1000000000000 | int) "P" (1000000000000000 | int) "Ki" (1024 | int) "Mi" (1048576 | int) "Gi" (1073741824 | int) "Ti" (1099511627776 | int) "Pi" (1125899906842624 | int) ) $unit (float64 0)) ))) "r")) ))) "r") -}}
 {{- $ok := $tmp_tuple_1.T2 -}}
 {{- $scale := ($tmp_tuple_1.T1 | float64) -}}
 {{- if (not $ok) -}}
@@ -7,9 +7,13 @@ import (
 	"os"
 
 	"example.com/example/astrewrites"
+	"example.com/example/calls"
 	"example.com/example/changing_inputs"
+	"example.com/example/closures"
 	"example.com/example/directives"
+	"example.com/example/failures"
 	"example.com/example/flowcontrol"
+	"example.com/example/imports"
 	"example.com/example/inputs"
 	"example.com/example/k8s"
 	"example.com/example/labels"
@@ -53,7 +57,7 @@ func main() {
 }
 
 func runChart(dot *helmette.Dot) (_ map[string]any, err any) {
-	defer func() { err = recover() }()
+	defer func() { err = helmette.AnnotateFailure(recover()) }()
 
 	switch dot.Chart.Name {
 	case "astrewrites":
@@ -79,6 +83,11 @@ func runChart(dot *helmette.Dot) (_ map[string]any, err any) {
 			"Typing": typing.Typing(dot),
 		}, nil
 
+	case "closures":
+		return map[string]any{
+			"Closures": closures.Closures(dot),
+		}, nil
+
 	case "directives":
 		return map[string]any{
 			"Directives": directives.Directives(),
@@ -94,6 +103,11 @@ func runChart(dot *helmette.Dot) (_ map[string]any, err any) {
 			"K8s": k8s.K8s(dot),
 		}, nil
 
+	case "failures":
+		return map[string]any{
+			"Failures": failures.Failures(dot),
+		}, nil
+
 	case "flowcontrol":
 		return map[string]any{
 			"FlowControl": flowcontrol.FlowControl(dot),
@@ -104,11 +118,21 @@ func runChart(dot *helmette.Dot) (_ map[string]any, err any) {
 			"Inputs": inputs.Inputs(dot),
 		}, nil
 
+	case "calls":
+		return map[string]any{
+			"Calls": calls.Calls(dot),
+		}, nil
+
 	case "changing_inputs":
 		return map[string]any{
 			"ChangingInputs": changing_inputs.ChangingInputs(dot),
 		}, nil
 
+	case "imports":
+		return map[string]any{
+			"Imports": imports.Imports(dot),
+		}, nil
+
 	case "syntax":
 		return map[string]any{
 			"Syntax": syntax.Syntax(),
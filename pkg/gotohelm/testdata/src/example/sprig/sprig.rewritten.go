@@ -2,9 +2,19 @@
 package sprig
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+	k8syaml "sigs.k8s.io/yaml"
 )
 
 type AStruct struct {
@@ -22,14 +32,22 @@ func Sprig(dot *helmette.Dot) map[string]any {
 		"concat":          concat(),
 		"default":         default_(),
 		"empty":           empty(),
+		"encoding":        encoding(),
 		"errTypes":        errTypes(),
 		"first":           first(),
 		"float":           float(),
 		"keys":            keys(),
+		"maps":            mapsFunctions(),
+		"slices":          slicesFunctions(),
+		"sort":            sortFunctions(),
 		"len":             lenTest(),
 		"min":             minFunc(),
 		"regex":           regex(),
+		"regexp":          regexpFunctions(),
 		"strings":         stringsFunctions(),
+		"stringsSearch":   stringsSearch(),
+		"strconv":         strconvFunctions(),
+		"formatInt":       formatInt(dot),
 		"toString":        toString(),
 		"trim":            trim(),
 		"unset":           unset(),
@@ -192,6 +210,274 @@ func stringsFunctions() []string {
 		helmette.Upper("hello WORLD"),
 		strings.ToLower("hello WORLD"),
 		strings.ToUpper("hello WORLD"),
+		strings.Title("hello wide world"),
+		strings.TrimSpace("\t hello world \n"),
+		strings.Repeat("ab", 3),
+		strings.Repeat("ab", 0),
+		strings.Join([]string{"a", "b", "c"}, ", "),
+		strings.Join([]string{}, ", "),
+	}
+}
+
+func stringsSearch() []any {
+	return []any{
+		strings.Split("a,b,c", ","),
+		strings.Split("a,b,c", ""),
+		strings.Split("", ","),
+		strings.Split("abc", "x"),
+		strings.SplitN("a.b.c.d", ".", 2),
+		strings.SplitN("a.b.c.d", ".", -1),
+		strings.SplitN("a.b.c.d", ".", 0),
+		strings.SplitN("", ".", 3),
+		strings.Fields("  hello \t wide\nworld\v "),
+		strings.Fields(" \u00a0 "),
+		strings.Contains("seafood", "foo"),
+		strings.Contains("seafood", "bar"),
+		strings.Contains("seafood", ""),
+		strings.HasPrefix("seafood", "sea"),
+		strings.HasPrefix("seafood", "food"),
+		strings.HasSuffix("seafood", "food"),
+		strings.HasSuffix("seafood", "sea"),
+		strings.Index("chicken", "ken"),
+		strings.Index("chicken", "dmr"),
+		strings.Index("chicken", ""),
+		strings.Index("héllo", "l"),
+		strings.Count("cheese", "e"),
+		strings.Count("five", ""),
+		strings.Count("héllo", ""),
+		strings.Count("", "e"),
+		strings.EqualFold("Go", "GO"),
+		strings.EqualFold("Go", "Gone"),
+	}
+}
+
+// formatInt fails if the base is invalid, as strconv.FormatInt panics.
+func formatInt(dot *helmette.Dot) string {
+	tmp_tuple_7 := helmette.Compact2(helmette.AsIntegral[int](dot.Values["base"]))
+	ok := tmp_tuple_7.T2
+	base := tmp_tuple_7.T1
+	if !ok {
+		base = 10
+	}
+	return strconv.FormatInt(-42, base)
+}
+
+func strconvFunctions() []any {
+	tmp_tuple_8 := helmette.Compact2(strconv.Atoi("234"))
+	positive := tmp_tuple_8.T1
+	tmp_tuple_9 := helmette.Compact2(strconv.Atoi("nope"))
+	invalid := tmp_tuple_9.T1
+	tmp_tuple_10 := helmette.Compact2(strconv.ParseBool("True"))
+	truthy := tmp_tuple_10.T1
+	tmp_tuple_11 := helmette.Compact2(strconv.ParseBool("0"))
+	falsy := tmp_tuple_11.T1
+	tmp_tuple_12 := helmette.Compact2(strconv.ParseBool("yes"))
+	bogus := tmp_tuple_12.T1
+	tmp_tuple_13 := helmette.
+
+		// Errors are reported as they would be in go.
+		Compact2(strconv.Atoi("-0042"))
+	parseErr := tmp_tuple_13.T2
+	parsed := tmp_tuple_13.T1
+	tmp_tuple_14 := helmette.Compact2(strconv.Atoi("4x2"))
+	garbledErr := tmp_tuple_14.T2
+	garbled := tmp_tuple_14.T1
+	tmp_tuple_15 := helmette.
+		// The clamped value, math.MaxInt, can't survive a JSON round trip.
+		Compact2(strconv.Atoi("99999999999999999999"))
+	overflowErr := tmp_tuple_15.T2
+	tmp_tuple_16 := helmette.Compact2(strconv.Atoi("-9223372036854775809"))
+	underflowErr := tmp_tuple_16.T2
+	underflow := tmp_tuple_16.T1
+	tmp_tuple_17 := helmette.Compact2(strconv.ParseBool("yes"))
+	yesErr := tmp_tuple_17.T2
+	yes := tmp_tuple_17.T1
+	if garbledErr != nil {
+		garbled = -1
+	}
+
+	return []any{
+		strconv.Itoa(0),
+		strconv.Itoa(-42),
+		strconv.Itoa(1234567),
+		positive,
+		invalid,
+		strconv.FormatInt(0, 10),
+		strconv.FormatInt(255, 16),
+		strconv.FormatInt(-255, 2),
+		strconv.FormatInt(1234567890, 36),
+		strconv.FormatInt(math.MinInt64, 10),
+		strconv.FormatInt(math.MinInt64, 16),
+		strconv.FormatInt(math.MaxInt64, 36),
+		truthy,
+		falsy,
+		bogus,
+		strconv.Quote("hello"),
+		strconv.Quote(`"quoted" \ and	tabbed`),
+		parsed,
+		parseErr == nil,
+		garbled,
+		fmt.Sprintf("%v", garbledErr),
+		fmt.Sprintf("%v", overflowErr),
+		underflow,
+		fmt.Sprintf("%v", underflowErr),
+		yes,
+		yesErr != nil,
+		fmt.Sprintf("%v", yesErr),
+	}
+}
+
+func slicesFunctions() []any {
+	ints := []int{3, 1, 2, 3, 3, 1}
+	strs := []string{"b", "c", "a"}
+
+	compacted := slices.Compact(ints)
+
+	var empty []int
+	slices.Sort(empty)
+	slices.Reverse(empty)
+
+	sorted := []int{10, 9, -1, 100}
+	slices.Sort(sorted)
+
+	reversed := []string{"x", "y", "z"}
+	slices.Reverse(reversed)
+
+	// Slices within maps are modified in place as well.
+	s := AStruct{Value: 1}
+	nested := map[string][]string{"strs": {"z", "a"}}
+	slices.Sort(strs)
+	slices.Sort(nested["strs"])
+
+	return []any{
+		slices.Contains(ints, 2),
+		slices.Contains(ints, 4),
+		slices.Contains(strs, "a"),
+		slices.Contains([]AStruct{{Value: 1}}, s),
+		slices.Index(ints, 3),
+		slices.Index(ints, 4),
+		slices.Index(strs, "c"),
+		compacted,
+		slices.Compact([]string{"a", "a", "b", "a"}),
+		slices.Compact(empty),
+		empty,
+		sorted,
+		reversed,
+		strs,
+		nested,
+	}
+}
+
+func mapsFunctions() []any {
+	src := map[string]int{"a": 1, "b": 2}
+	dst := map[string]int{"b": 0, "c": 3}
+	maps.Copy(dst, src)
+
+	clone := maps.Clone(src)
+	clone["z"] = 26
+
+	var nilMap map[string]int
+
+	return []any{
+		src,
+		dst,
+		clone,
+		maps.Clone(nilMap) == nil,
+	}
+}
+
+type person struct {
+	Name string
+	Age  int
+}
+
+func sortFunctions() []any {
+	people := []person{
+		{Name: "Alice", Age: 30},
+		{Name: "Bob", Age: 25},
+		{Name: "Carol", Age: 35},
+		{Name: "Dave", Age: 25},
+	}
+	sort.Slice(people, func(i, j int) bool {
+		return people[i].Age < people[j].Age
+	})
+
+	byName := []string{"b", "a", "c"}
+	sort.Slice(byName, func(i, j int) bool {
+		return byName[i] > byName[j]
+	})
+
+	strs := []string{"z", "x", "y"}
+	sort.Strings(strs)
+
+	return []any{people, byName, strs}
+}
+
+func encoding() []any {
+	data := map[string]any{
+		"b": []int{1, 2},
+		"a": "<tag> & more",
+		"c": map[string]any{},
+		"d": AStruct{Value: 1},
+	}
+	tmp_tuple_18 := helmette.Compact2(json.Marshal(data))
+	asJSON := tmp_tuple_18.T1
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("hello world"))
+	tmp_tuple_19 := helmette.Compact2(base64.StdEncoding.DecodeString(encoded))
+	decoded := tmp_tuple_19.T1
+
+	return []any{
+		helmette.Sha256Sum("hello world"),
+		helmette.Sha256Sum(string(asJSON)),
+		string(asJSON),
+		encoded,
+		string(decoded),
+		base64.StdEncoding.EncodeToString([]byte("")),
+	}
+}
+
+// marshalYAML is transpiled but not compared as the local renderer implements
+// toYaml with helmette.ToYaml rather than helm's sigs.k8s.io/yaml based
+// version.
+func marshalYAML(value any) string {
+	tmp_tuple_20 := helmette.Compact2(k8syaml.Marshal(value))
+	err := tmp_tuple_20.T2
+	out := tmp_tuple_20.T1
+	if err != nil {
+		panic(err)
+	}
+	return string(out)
+}
+
+var imageTag = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?(-[\w.]+)?$`)
+
+func regexpFunctions() []any {
+	address := regexp.MustCompile(`(?P<host>[\w.-]+):(?P<port>\d+)`)
+	words := regexp.MustCompile(`\w+`)
+	tmp_tuple_21 := helmette.Compact2(regexp.MatchString(`^\d+$`, "1234"))
+	matched := tmp_tuple_21.T1
+	tmp_tuple_22 := helmette.Compact2(regexp.Compile(`(?i)redpanda`))
+	compiled := tmp_tuple_22.T1
+
+	return []any{
+		imageTag.MatchString("v23.2.1"),
+		imageTag.MatchString("latest"),
+		imageTag.FindStringSubmatch("v23.2"),
+		imageTag.FindStringSubmatch("23.2.1-rc1"),
+		imageTag.FindStringSubmatch("latest"),
+		address.FindStringSubmatch("brokers: broker-0.redpanda:9093, broker-1:9094"),
+		address.FindString("brokers: broker-0.redpanda:9093, broker-1:9094"),
+		address.String(),
+		words.FindAllString("a bb ccc", -1),
+		words.FindAllString("a bb ccc", 2),
+		words.FindAllString("!!", -1),
+		words.ReplaceAllString("hello world", "<$0>"),
+		words.ReplaceAllLiteralString("hello world", "$0"),
+		regexp.MustCompile(`\s*,\s*`).Split("a , b,c", -1),
+		regexp.QuoteMeta("a.b*c"),
+		matched,
+		compiled.MatchString("RedPanda"),
 	}
 }
 
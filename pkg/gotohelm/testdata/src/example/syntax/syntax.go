@@ -4,9 +4,20 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
 	corev1 "k8s.io/api/core/v1"
 )
 
+type (
+	ImageTag      string
+	ContainerName string
+)
+
+const (
+	DefaultTag ImageTag = "latest"
+	Suffix              = "-suffix"
+)
+
 const (
 	AStrConst           = "1234"
 	AnIntConst          = 1234
@@ -60,6 +71,10 @@ func Syntax() map[string]any {
 		"binaryExprs":     binaryExprs(),
 		"instance-method": instanceMethod(),
 		"append":          appends(),
+		"assignOps":       assignOps(),
+		"concatenation":   concatenation("redpanda", "v24.1.1"),
+		"variadics":       variadics(),
+		"mapLiterals":     mapLiterals(),
 	}
 }
 
@@ -142,8 +157,7 @@ func workingWithString() map[string]any {
 	amount = amount[:len(amount)-1]
 
 	if unit == "i" {
-		// TODO string + string not implemented.
-		unit = fmt.Sprintf("%s%s", amount[len(amount)-1:], unit)
+		unit = amount[len(amount)-1:] + unit
 		amount = amount[:len(amount)-1]
 	}
 
@@ -293,3 +307,245 @@ func appends() [][]int {
 		append(y, 1, 2, 3, 4),
 	}
 }
+
+func assignOps() map[string]any {
+	i := 10
+	i += 5
+	i -= 3
+	i *= 4
+	i /= 5
+	i %= 7
+
+	flags := 1
+	flags |= 4
+	flags |= 6
+
+	negative := -8
+	negative |= 3
+
+	i64 := int64(100)
+	i64 += 23
+	i64 /= 2
+
+	f := 1.5
+	f += 2.25
+	f *= 2
+	f -= 0.5
+	f /= 4
+
+	str := "hello"
+	str += ", "
+	str += "world"
+
+	ts := TestStruct{Mult: 2, SomeString: "a"}
+	ts.Mult *= 21
+	ts.SomeString += "b"
+
+	counts := map[string]int{"a": 1}
+	counts["a"] += 1
+	counts["b"] += 10
+
+	// The index is evaluated exactly once.
+	state := map[string]int{}
+	counts[nextKey(state)] += 5
+
+	// So is the receiver of a selector.
+	tallies := map[string]*tally{"key-1": {}, "key-2": {}, "key-3": {}}
+	tallyKeys := map[string]int{}
+	tallies[nextKey(tallyKeys)].N += 2
+	tallies[nextKey(tallyKeys)].N++
+
+	keys := []string{"x", "y"}
+	names := map[string]string{}
+	names[keys[0]] += "first"
+	names[keys[1]] += "second"
+
+	// Lists can't be modified in place, so modified copies of slices are
+	// assigned back to them.
+	xs := []int{1, 2, 3}
+	xs[0] += 5
+	xs[2]++
+	xs[1] = 7
+
+	arr := [2]string{"a", "b"}
+	arr[1] += "c"
+
+	holder := sliceHolder{Values: []float64{1.5, 2}}
+	holder.Values[1] *= 4
+
+	nested := map[string][]int{"a": {1, 2}}
+	nested["a"][1] -= 3
+	nested["a"][0] = 9
+
+	step := 3
+	floor := 0
+	var down []int
+	for n := 10; n > floor; n -= step {
+		down = append(down, n)
+	}
+
+	return map[string]any{
+		"int":      i,
+		"flags":    flags,
+		"negative": negative,
+		"int64":    i64,
+		"float":    f,
+		"string":   str,
+		"struct":   ts,
+		"counts":   counts,
+		"names":    names,
+		"down":     down,
+		"state":    state,
+		"slice":    xs,
+		"array":    arr,
+		"holder":   holder,
+		"nested":   nested,
+		"tallies":  tallies,
+	}
+}
+
+type tally struct {
+	N int
+}
+
+type sliceHolder struct {
+	Values []float64
+}
+
+func nextKey(state map[string]int) string {
+	state["calls"] += 1
+	return fmt.Sprintf("key-%d", state["calls"])
+}
+
+func concatenation(name ContainerName, tag ImageTag) map[string]any {
+	image := "docker.io/redpanda:" + tag
+	sidecar := name + "-sidecar"
+	str := string(name)
+
+	return map[string]any{
+		"untyped":       "a" + "b" + "c",
+		"constants":     AStrConst + Suffix,
+		"namedConstant": DefaultTag + Suffix,
+		"named":         image,
+		"namedMixed":    sidecar + Suffix + name,
+		"chained":       str + "/" + string(tag) + "@" + str,
+		"parenthesized": str + ("(" + string(tag) + ")"),
+		"conversion":    string(name+"-") + str,
+		"comparison":    image == "docker.io/redpanda:"+tag,
+		"defaulted":     str + string(DefaultTag),
+	}
+}
+
+func variadics() map[string]any {
+	base := map[string]string{"app": "redpanda"}
+	extra := []map[string]string{{"a": "1"}, {"b": "2"}}
+	nums := []int{4, 5, 6}
+	var none []int
+
+	var parts []any
+	for _, n := range nums {
+		parts = append(parts, n)
+	}
+	var noArgs []any
+	nested := []any{extra}
+
+	sum := func(xs ...int) int {
+		total := 0
+		for _, x := range xs {
+			total += x
+		}
+		return total
+	}
+
+	return map[string]any{
+		"none":          labels(base),
+		"one":           labels(base, map[string]string{"a": "1"}),
+		"many":          labels(base, extra[0], extra[1]),
+		"spread":        labels(base, extra...),
+		"count":         []any{count(), count(1, 2, 3), count(nums...), count(none...)},
+		"nil":           variadicArgs(),
+		"closure":       []int{sum(), sum(1, 2), sum(nums...)},
+		"method":        (&TestStruct{SomeString: "-"}).Join("a", "b", "c"),
+		"sprintf":       fmt.Sprintf("%s/%s:%s", []any{"docker.io", "redpanda", "latest"}...),
+		"sprintfFixed":  fmt.Sprintf("%s/%s", append([]any{"docker.io"}, "redpanda")...),
+		"min":           helmette.Min([]int64{3, 1, 2}...),
+		"quote":         helmette.Quote([]any{"a", 1}...),
+		"concat":        helmette.Concat([][]int{{1}, {2, 3}}...),
+		"spreadBuiltin": helmette.Concat(append([][]int{{1}}, nums)...),
+		"sprintfSpread": []string{fmt.Sprintf("%d-%d-%d", parts...), helmette.Printf("none", noArgs...), fmt.Sprintf("%v", nested...)},
+	}
+}
+
+func labels(base map[string]string, extra ...map[string]string) map[string]string {
+	out := map[string]string{}
+	for k, v := range base {
+		out[k] = v
+	}
+	for _, m := range extra {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func count(xs ...int) int {
+	return len(xs)
+}
+
+func variadicArgs(xs ...string) []string {
+	return xs
+}
+
+func (ts *TestStruct) Join(parts ...string) string {
+	out := ""
+	for i, part := range parts {
+		if i > 0 {
+			out += ts.SomeString
+		}
+		out += part
+	}
+	return out
+}
+
+func mapLiterals() []any {
+	key := "variable"
+	var tag ImageTag = "v1"
+
+	strings := map[string]int{
+		"literal":                    1,
+		`raw`:                        2,
+		AStrConst:                    3,
+		Suffix + "-computed":         4,
+		key:                          5,
+		fmt.Sprintf("%s-%d", key, 6): 6,
+	}
+
+	named := map[ImageTag]bool{
+		DefaultTag: true,
+		tag:        false,
+	}
+
+	protocols := map[corev1.Protocol]string{
+		corev1.ProtocolTCP: "tcp",
+	}
+
+	n := 3
+	ints := map[int]string{
+		1:          "one",
+		AnIntConst: "const",
+		n * 10:     "computed",
+	}
+	ints[n] = "three"
+	ints[1] = ints[1] + "!"
+	delete(ints, AnIntConst)
+	_, hasThree := ints[3]
+	_, hasFour := ints[4]
+
+	sum := 0
+	for k := range ints {
+		sum = sum + k
+	}
+
+	return []any{strings, named, protocols, ints, ints[n], hasThree, hasFour, sum}
+}
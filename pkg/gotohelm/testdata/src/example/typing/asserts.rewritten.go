@@ -2,6 +2,8 @@
 package typing
 
 import (
+	"fmt"
+
 	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
 )
 
@@ -31,17 +33,42 @@ func typeAssertions(dot *helmette.Dot) string {
 }
 
 func typeSwitching(dot *helmette.Dot) string {
-	return "Not yet supported"
-	// switch dot.Values["t"].(type) {
-	// case int:
-	// 	return "it's an int!"
-	// case string:
-	// 	return "it's a string!"
-	// case float64:
-	// 	return "it's a float64!"
-	// case bool:
-	// 	return "it's a bool!"
-	// default:
-	// 	return "it's something else"
-	// }
+	switch t := dot.Values["t"].(type) {
+	case string:
+		return fmt.Sprintf("it's a string: %q", t)
+	case bool:
+		if t {
+			return "it's true!"
+		}
+		return "it's false!"
+	case []any, map[string]any:
+		return fmt.Sprintf("it's a collection of length %d", helmette.Len(t))
+	case nil:
+		return "it's nil!"
+	default:
+		tmp_tuple_4 := helmette.Compact2(helmette.AsNumeric(t))
+		ok_4 := tmp_tuple_4.T2
+		if ok_4 {
+			return "it's a number!"
+		}
+		return "it's something else!"
+	}
+}
+
+func typeSwitchingNoBinding(dot *helmette.Dot) []string {
+	var out []string
+	for _, v := range []any{dot.Values["t"], "", true, nil, []any{}} {
+		switch v.(type) {
+		case any:
+			out = append(out, "any")
+		}
+
+		switch v.(type) {
+		case bool:
+			out = append(out, "bool")
+		default:
+			out = append(out, "not a bool")
+		}
+	}
+	return out
 }
@@ -0,0 +1,74 @@
+package typing
+
+type Counter struct {
+	Name  string
+	Count int
+	Tags  [2]string
+}
+
+func (c Counter) Incremented() Counter {
+	c.Count++
+	return c
+}
+
+func (c Counter) Label() string {
+	return c.Name
+}
+
+func (c *Counter) Increment() {
+	c.Count++
+}
+
+func rename(c Counter, name string) Counter {
+	c.Name = name
+	return c
+}
+
+func label(c Counter) string {
+	return c.Name
+}
+
+type Holder struct {
+	C Counter
+}
+
+func setHolderName(h *Holder) {
+	h.C.Name = "mutated"
+}
+
+func copies() []any {
+	a := Counter{Name: "a", Count: 1}
+
+	// Assignment copies a.
+	b := a
+	b.Count = 2
+
+	// Value receivers and arguments are copies.
+	c := a.Incremented()
+	d := rename(a, "d")
+
+	// Pointer receivers mutate the original.
+	e := a
+	e.Increment()
+
+	// Arrays are copied as well.
+	f := Counter{Name: "f", Tags: [2]string{"x", "y"}}
+	f.Tags = a.Tags
+	f.Tags = [2]string{f.Tags[0], "tagged"}
+
+	// Copies that are never mutated share their value.
+	g := a
+
+	// Functions may mutate values through pointers to them.
+	h := &Holder{C: Counter{Name: "orig"}}
+	i := h.C
+	setHolderName(h)
+
+	// Range values are copies of the elements.
+	counters := []Counter{{Name: "x"}, {Name: "y"}}
+	for _, c := range counters {
+		c.Name = "ranged"
+	}
+
+	return []any{a, b, c, d, e, f, a.Label(), label(g), i.Name, h.C.Name, counters}
+}
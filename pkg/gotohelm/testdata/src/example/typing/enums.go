@@ -0,0 +1,85 @@
+package typing
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+	_
+	Thursday
+)
+
+func (d Weekday) IsWeekend() bool {
+	return d == Sunday
+}
+
+func (d Weekday) String() string {
+	switch d {
+	case Sunday:
+		return "Sunday"
+	case Monday:
+		return "Monday"
+	case Tuesday:
+		return "Tuesday"
+	}
+	return fmt.Sprintf("Weekday(%d)", int(d))
+}
+
+type AuthMethod string
+
+const (
+	AuthMethodNone AuthMethod = "none"
+	AuthMethodSASL AuthMethod = "sasl"
+)
+
+func (m AuthMethod) Enabled() bool {
+	return m != AuthMethodNone && m != ""
+}
+
+// defaultLabels and wellKnownKeys are package level variables, which are
+// transpiled into zero argument functions.
+var (
+	defaultLabels = map[string]string{
+		"app.kubernetes.io/managed-by": "Helm",
+	}
+
+	wellKnownKeys = []string{"auth", "tls"}
+
+	defaultMethod = AuthMethodSASL
+
+	zeroDay Weekday
+)
+
+var firstWeekday = Weekday(len(wellKnownKeys) - 1)
+
+func enums(dot *helmette.Dot) []any {
+	method, _ := dot.Values["method"].(string)
+
+	labels := map[string]string{}
+	for k, v := range defaultLabels {
+		labels[k] = v
+	}
+	labels["method"] = string(AuthMethod(method))
+
+	return []any{
+		Sunday.IsWeekend(),
+		Monday.IsWeekend(),
+		Thursday,
+		Thursday.String(),
+		Weekday(2).String(),
+		AuthMethod(method).Enabled(),
+		defaultMethod.Enabled(),
+		labels,
+		wellKnownKeys,
+		len(wellKnownKeys),
+		zeroDay.String(),
+		firstWeekday.String(),
+	}
+}
@@ -0,0 +1,47 @@
+//go:build rewrites
+package typing
+
+import (
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+func equality(dot *helmette.Dot) []any {
+	a := Object{Key: "a", WithTag: 1}
+	b := Object{Key: "a", WithTag: 1}
+	c := Object{Key: "c"}
+
+	x := [2]string{"a", "b"}
+	y := [2]string{"a", "b"}
+
+	t := dot.Values["t"]
+	// Structs decoded from values may omit zero valued fields.
+	decoded := helmette.UnmarshalInto[Object](dot.Values["object"])
+	var boxed any = a
+
+	match := "none"
+	switch c {
+	case a:
+		match = "a"
+	case Object{Key: "c"}:
+		match = "c"
+	}
+
+	return []any{
+		// Structs and arrays are compared by value.
+		a == b,
+		a != c,
+		a == c,
+		x == y,
+		x != [2]string{"b", "a"},
+		match,
+		// Interfaces compare their dynamic values.
+		boxed == a,
+		boxed != c,
+		t == "a string",
+		t != true,
+		t == boxed,
+		t == nil,
+		decoded == Object{Key: "a"},
+		decoded != Object{},
+	}
+}
@@ -0,0 +1,84 @@
+package typing
+
+import (
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+)
+
+type Pair[K, V comparable] struct {
+	Key   K
+	Value V
+}
+
+func (p *Pair[K, V]) Set(value V) {
+	p.Value = value
+}
+
+func (p Pair[K, V]) Or(value V) V {
+	var zero V
+	if p.Value == zero {
+		return value
+	}
+	return p.Value
+}
+
+type Listeners[T any] map[string]T
+
+func (l Listeners[T]) Get(name string) T {
+	if listener, ok := l[name]; ok {
+		return listener
+	}
+	var zero T
+	return zero
+}
+
+func generics(dot *helmette.Dot) map[string]any {
+	pair := Pair[string, int]{Key: "a"}
+	unset := Pair[string, string]{Key: "b"}
+	pair.Set(3)
+
+	listeners := Listeners[Pair[string, int]]{"a": pair}
+
+	return map[string]any{
+		"firstOr": []any{firstOr([]int{}, 3), firstOr([]string{"a"}, "b"), firstOr[float64](nil, 1.5)},
+		"zeros":   []any{zero[int](), zero[string](), zero[bool](), zero[float64](), zero[[]int](), zero[Pair[string, int]]()},
+		"is":      []any{is[string](dot.Values["t"]), is[bool](dot.Values["t"]), is[[]any](dot.Values["t"]), is[map[string]any](dot.Values["t"])},
+		"sum":     []any{sum([]int{1, 2, 3}), sum([]float64{0.5, 1})},
+		"pair":    []any{pair, pair.Or(5), unset.Or("default")},
+		"get":     []any{listeners.Get("a"), listeners.Get("b")},
+		"wrap":    []any{wrap(1), wrap("one")},
+		"twice":   []any{twice("x"), twice(2)},
+	}
+}
+
+func firstOr[T any](xs []T, fallback T) T {
+	if len(xs) > 0 {
+		return xs[0]
+	}
+	return fallback
+}
+
+func zero[T any]() T {
+	var z T
+	return z
+}
+
+func is[T any](x any) bool {
+	_, ok := x.(T)
+	return ok
+}
+
+func sum[T int | float64](xs []T) T {
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func wrap[T comparable](value T) Pair[string, T] {
+	return Pair[string, T]{Key: "wrapped", Value: value}
+}
+
+func twice[T any](value T) []T {
+	return []T{firstOr([]T{}, value), firstOr([]T{value}, zero[T]())}
+}
@@ -0,0 +1,117 @@
+//go:build rewrites
+package typing
+
+import (
+	"fmt"
+)
+
+type Shape interface {
+	Area() int
+	Name() string
+	Scale(factor int)
+}
+
+type Labeler interface {
+	Label() string
+}
+
+type Rect struct {
+	W int
+	H int
+}
+
+func (r *Rect) Area() int {
+	return r.W * r.H
+}
+
+func (r *Rect) Name() string {
+	return "rect"
+}
+
+func (r *Rect) Scale(factor int) {
+	r.W = r.W * factor
+	r.H = r.H * factor
+}
+
+type Square struct {
+	Side int
+}
+
+func (s *Square) Area() int {
+	return s.Side * s.Side
+}
+
+func (s *Square) Name() string {
+	return "square"
+}
+
+func (s *Square) Scale(factor int) {
+	s.Side = s.Side * factor
+}
+
+type Tag string
+
+func (t Tag) Label() string {
+	return "tag:" + string(t)
+}
+
+type Canvas struct {
+	Main   Shape
+	Shapes []Shape
+}
+
+func newShape(square bool) Shape {
+	if square {
+		return &Square{Side: 1}
+	}
+	return &Rect{W: 2, H: 3}
+}
+
+func describe(s Shape) string {
+	return fmt.Sprintf("%s:%d", s.Name(), s.Area())
+}
+
+func largest(shapes ...Shape) Shape {
+	var out Shape
+	for _, s := range shapes {
+		if out == nil || s.Area() > out.Area() {
+			out = s
+		}
+	}
+	return out
+}
+
+func interfaces() []any {
+	shapes := []Shape{&Rect{W: 1, H: 2}, &Square{Side: 3}, newShape(true), newShape(false)}
+
+	var out []any
+	for _, s := range shapes {
+		// Pointer receivers mutate the underlying value.
+		s.Scale(2)
+		out = append(out, describe(s))
+	}
+
+	canvas := Canvas{Main: shapes[1], Shapes: shapes[2:]}
+	canvas.Main.Scale(2)
+
+	var tag Labeler = Tag("a")
+	labels := map[string]Labeler{"counter": Counter{Name: "c"}, "tag": tag}
+
+	var none Shape
+
+	return append(
+		out,
+		canvas.Main.Area(),
+		shapes[1].Area(),
+		len(canvas.Shapes),
+		largest(shapes...).Name(),
+		largest(shapes[0], shapes[2]).Name(),
+		labels["counter"].Label(),
+		tag.Label(),
+		tag == Tag("a"),
+		tag != labels["counter"],
+		none == nil,
+		shapes[0] != nil,
+		any(shapes[0]),
+	)
+}
@@ -1,5 +1,9 @@
 package typing
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 type NestedEmbeds struct {
 	WithEmbed
 }
@@ -77,3 +81,24 @@ func alsoMe() WithEmbed {
 		Exclude: "Exclude",
 	}
 }
+
+func compositeLits() []any {
+	var zero [2]string
+
+	return []any{
+		// Positional struct literals.
+		Object{"positional", 1},
+		WithEmbed{Object{"embedded", 2}, "excluded", nil, nil},
+		// Elided composite literals.
+		[]Object{{"elided", 3}, {Key: "keyed"}},
+		[]*Object{{Key: "pointer"}},
+		map[string]Object{"value": {"mapped", 4}},
+		[]corev1.EnvVar{{Name: "NAME", Value: "value"}},
+		[][]int{{1}, {2, 3}},
+		// Arrays and indexed elements.
+		[3]int{1, 2},
+		[...]string{"a", "b"},
+		[]string{2: "c", "d"},
+		zero,
+	}
+}
@@ -14,6 +14,13 @@ func Typing(dot *helmette.Dot) map[string]any {
 		"typeTesting":       typeTesting(dot),
 		"typeAssertions":    typeSwitching(dot),
 		"typeSwitching":     typeSwitching(dot),
+		"typeSwitchingNB":   typeSwitchingNoBinding(dot),
 		"nestedFieldAccess": nestedFieldAccess(),
+		"generics":          generics(dot),
+		"compositeLits":     compositeLits(),
+		"enums":             enums(dot),
+		"copies":            copies(),
+		"equality":          equality(dot),
+		"interfaces":        interfaces(),
 	}
 }
@@ -0,0 +1,100 @@
+//go:build rewrites
+// Package unsupported is a collection of go constructs that gotohelm does not
+// support. It's used to assert the diagnostics reported by the transpiler and
+// Analyzer rather than the output of a transpiled chart.
+package unsupported
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func Unsupported(dot *helmette.Dot) map[string]any {
+	x := dot.Values["x"]
+
+	defer func() {}() // want `unhandled ast.Stmt`
+
+	go func() {}() // want `unhandled ast.Stmt`
+
+	ch := make(chan int)
+	tmp_tuple_1 := // want `unsupported golang builtin "make"` `unhandled ast.Expr`
+		helmette.Compact2(strconv.ParseFloat(os.Getenv("N"), 64))
+	f := tmp_tuple_1.T1 // want `unsupported function "strconv.ParseFloat". Consider using helmette.Float64 instead` `unsupported function "os.Getenv"`
+	n := int(f)
+
+	n <<= 1 // want `No matching \*ast.AssignStmt signature for \[int << untyped int\]`
+
+	incr := func() { n++ } // want `function literals may not assign to captured variables \(n\)`
+	incr()
+
+	count := 0
+	get := func() int { return count }
+	count = 5 // want `variables captured by function literals may not be reassigned after the literal \(count\)`
+
+	counter++ // want `package level variables may not be assigned to`
+
+	args := os.Args
+	tmp_tuple_2 := // want `only package level variables of transpiled packages may be referenced`
+		helmette.Compact2(base64.StdEncoding.DecodeString("x"))
+	err := tmp_tuple_2.T2
+	decoded := tmp_tuple_2.T1 // want `the error returned by \(\*encoding/base64.Encoding\).DecodeString can't be reproduced in templates and must be discarded`
+
+	self := dot
+
+	return map[string]any{
+		"chan":     ch,
+		"n":        n,
+		"count":    get(),
+		"decoded":  []any{decoded, err},
+		"args":     args,
+		"typeOf":   reflect.TypeOf(x),            // want `unsupported function "reflect.TypeOf". Consider using helmette.TypeOf instead`
+		"assert":   x.(int),                      // want `type assertions on numeric types are unreliable`
+		"cap":      cap([]int{}),                 // want `unsupported golang builtin "cap"`
+		"keys":     map[bool]string{true: "one"}, // want `map keys must be strings or integers`
+		"intstr":   intstr.Parse("1"),            // want `unsupported function "k8s.io/apimachinery/pkg/util/intstr.Parse"`
+		"switch":   numericSwitch(x),
+		"same":     dot == self,                                    // want `pointers to structs and arrays may not be compared`
+		"b64":      base64.URLEncoding.EncodeToString([]byte("x")), // want `only base64.StdEncoding is supported`
+		"sha256":   sha256.Sum256([]byte("x")),                     // want `unsupported function "crypto/sha256.Sum256". Consider using helmette.Sha256Sum instead`
+		"regexp":   regexp.MustCompile(`x(?=y)`),                   // want `invalid regular expression`
+		"posix":    regexp.MustCompilePOSIX(`x+`),                  // want `unsupported function "regexp.MustCompilePOSIX". Consider using regexp.MustCompile instead`
+		"submatch": submatch(x.(string)),
+		"spread":   helmette.Concat(dot.Values["lists"].([][]any)...), // want `spread arguments of builtins must be slice literals or appends onto them`
+	}
+}
+
+func numericSwitch(x any) string {
+	switch x.(type) {
+	case int: // want `type checks on numeric types are unreliable`
+		return "int"
+	case string:
+		return "string"
+	}
+	return "other"
+}
+
+func submatch(pattern string) []string {
+	return regexp.MustCompile(pattern).FindStringSubmatch("x") // want `FindStringSubmatch may only be called on regular expressions compiled from constant patterns`
+}
+
+func recovers() any {
+	return recover() // want `unsupported golang builtin "recover"`
+}
+
+var counter int
+
+type Namer interface {
+	Name() string
+}
+
+func stringer(n Namer) fmt.Stringer {
+	return n.(fmt.Stringer) // want `type assertions on interfaces with methods are not supported`
+}
@@ -9,31 +9,65 @@ import (
 	"go/format"
 	"go/token"
 	"go/types"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/errors"
+	"github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/types/typeutil"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
 )
 
 var directiveRE = regexp.MustCompile(`\+gotohelm:([\w\.-]+)=([\w\.-]+)`)
 
+// alternatives maps the ids of unsupported functions to supported
+// equivalents. It's used to provide hints in [Unsupported] diagnostics.
+var alternatives = map[string]string{
+	"crypto/sha256.Sum256":           "helmette.Sha256Sum",
+	"encoding/json.Unmarshal":        "helmette.FromJSON",
+	"github.com/imdario/mergo.Merge": "helmette.Merge",
+	"gopkg.in/yaml.v3.Marshal":       "helmette.ToYaml",
+	"reflect.TypeOf":                 "helmette.TypeOf",
+	"regexp.CompilePOSIX":            "regexp.Compile",
+	"regexp.MustCompilePOSIX":        "regexp.MustCompile",
+	"strconv.ParseFloat":             "helmette.Float64",
+	"time.ParseDuration":             "helmette.MustDuration",
+}
+
+// inPlace is the set of ids of functions that modify their first argument, a
+// slice, in place. Lists may not be modified in templates, so the
+// transpilations of such functions return the modified slice instead, which
+// is assigned back to their argument.
+var inPlace = map[string]bool{
+	"slices.Reverse": true,
+	"slices.Sort":    true,
+	"sort.Slice":     true,
+	"sort.Strings":   true,
+}
+
 type Unsupported struct {
 	Node ast.Node
 	Msg  string
 	Fset *token.FileSet
+	// Alternative is an optional suggestion of a supported equivalent to the
+	// unsupported node, typically a helmette function.
+	Alternative string
 }
 
 func (u *Unsupported) Error() string {
 	var b bytes.Buffer
 	fmt.Fprintf(&b, "unsupported ast.Node: %T\n", u.Node)
 	fmt.Fprintf(&b, "%s\n", u.Msg)
-	fmt.Fprintf(&b, "%s\n\t", u.Fset.PositionFor(u.Node.Pos(), false).String())
+	if u.Alternative != "" {
+		fmt.Fprintf(&b, "consider using %s instead\n", u.Alternative)
+	}
+	fmt.Fprintf(&b, "%s\n\t", u.Position().String())
 	if err := format.Node(&b, u.Fset, u.Node); err != nil {
 		panic(err) // Oh the irony
 	}
@@ -41,11 +75,65 @@ func (u *Unsupported) Error() string {
 	return b.String()
 }
 
+// Position returns the [token.Position] of the unsupported node within the
+// original source. See [LoadPackages] for details.
+func (u *Unsupported) Position() token.Position {
+	return u.Fset.PositionFor(u.Node.Pos(), true)
+}
+
+// Kind returns the go type of the unsupported node. e.g. *ast.CallExpr
+func (u *Unsupported) Kind() string {
+	return fmt.Sprintf("%T", u.Node)
+}
+
+// Diagnostics is the collection of [Unsupported] nodes encountered while
+// transpiling a package with [TranspileOptions.Diagnose] enabled. They are
+// sorted by position.
+type Diagnostics []*Unsupported
+
+func (d Diagnostics) Error() string {
+	var b strings.Builder
+	for i, u := range d {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(u.Error())
+	}
+	return b.String()
+}
+
+type TranspileOptions struct {
+	// Diagnose, if true, causes transpilation to continue past any
+	// unsupported nodes. All encountered [Unsupported]s are returned as
+	// [Diagnostics] rather than aborting upon the first.
+	Diagnose bool
+	// AnnotateFailures, if true, appends the go function name and file:line
+	// of the originating call to the message of every `fail` or `required`
+	// emitted from panic, [helmette.Fail] and [helmette.Required].
+	// See [helmette.FailureSource] for the exact format and
+	// [helmette.AnnotateFailure] for an equivalent to use in go code.
+	AnnotateFailures bool
+	// Calls are [CallFunc]s, by the id of the function or method they
+	// transpile, to use in addition to, or in place of, those registered with
+	// [RegisterCall].
+	Calls map[string]CallFunc
+}
+
 type Chart struct {
 	Files []*File
 }
 
 func Transpile(pkg *packages.Package) (_ *Chart, err error) {
+	return TranspileWithOptions(pkg, TranspileOptions{})
+}
+
+func TranspileWithOptions(pkg *packages.Package, opts TranspileOptions) (*Chart, error) {
+	loadShims()
+
+	return transpile(pkg, opts)
+}
+
+func transpile(pkg *packages.Package, opts TranspileOptions) (_ *Chart, err error) {
 	defer func() {
 		switch v := recover().(type) {
 		case nil:
@@ -56,27 +144,125 @@ func Transpile(pkg *packages.Package) (_ *Chart, err error) {
 		}
 	}()
 
-	t := &Transpiler{
+	t := newTranspiler(pkg, opts)
+
+	chart := t.Transpile()
+	transpilers := []*Transpiler{t}
+
+	// Helper packages are transpiled in their entirety into their own
+	// `_<namespace>.tpl` file. Namespaces must be unique as they're shared
+	// across all files of the chart.
+	namespaces := map[string]string{t.namespaceFor(pkg.Types): pkg.PkgPath}
+	for _, helper := range helperPackages(pkg) {
+		ht := newTranspiler(helper, opts)
+		ht.instances = t.instances
+		ht.root = pkg
+
+		file := ht.transpileHelper()
+		transpilers = append(transpilers, ht)
+
+		ns := ht.namespaceFor(helper.Types)
+		if other, ok := namespaces[ns]; ok {
+			return nil, errors.Newf("packages %q and %q share the namespace %q. Use a +gotohelm:namespace directive to disambiguate them", other, helper.PkgPath, ns)
+		}
+		namespaces[ns] = helper.PkgPath
+
+		chart.Files = append(chart.Files, file)
+	}
+
+	// Generic functions of a helper package may be instantiated by packages
+	// that have been transpiled after it. Keep transpiling instances until
+	// there are none left.
+	for pending := true; pending; {
+		pending = false
+		for _, tr := range transpilers {
+			pending = tr.transpileInstances() || pending
+		}
+	}
+
+	var diagnostics Diagnostics
+	for _, tr := range transpilers {
+		diagnostics = append(diagnostics, tr.diagnostics...)
+	}
+
+	if len(diagnostics) > 0 {
+		// NB: Positions are only comparable within the same FileSet. All
+		// packages loaded via LoadPackages share one.
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			return diagnostics[i].Node.Pos() < diagnostics[j].Node.Pos()
+		})
+		return nil, diagnostics
+	}
+
+	return chart, nil
+}
+
+// isHelperPackage returns true if the given package is a helper package. That
+// is a package, other than helmette itself, that imports helmette and may
+// therefore be transpiled.
+func isHelperPackage(pkg *types.Package) bool {
+	if pkg.Path() == shimsPkgPath {
+		return false
+	}
+
+	for _, imported := range pkg.Imports() {
+		if imported.Path() == shimsPkgPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// helperPackages returns all helper packages (See [isHelperPackage])
+// transitively imported by root via other helper packages, sorted by path.
+func helperPackages(root *packages.Package) []*packages.Package {
+	seen := map[string]bool{root.PkgPath: true}
+	toVisit := []*packages.Package{root}
+
+	var helpers []*packages.Package
+	var pkg *packages.Package
+	for len(toVisit) > 0 {
+		pkg, toVisit = toVisit[0], toVisit[1:]
+
+		for _, imported := range pkg.Imports {
+			if seen[imported.PkgPath] || !isHelperPackage(imported.Types) {
+				continue
+			}
+
+			seen[imported.PkgPath] = true
+			helpers = append(helpers, imported)
+			toVisit = append(toVisit, imported)
+		}
+	}
+
+	sort.Slice(helpers, func(i, j int) bool {
+		return helpers[i].PkgPath < helpers[j].PkgPath
+	})
+
+	return helpers
+}
+
+func newTranspiler(pkg *packages.Package, opts TranspileOptions) *Transpiler {
+	return &Transpiler{
 		Package:   pkg,
 		Fset:      pkg.Fset,
 		TypesInfo: pkg.TypesInfo,
 		Files:     pkg.Syntax,
 
-		packages:   mkPkgTree(pkg),
-		namespaces: map[*types.Package]string{},
-		names:      map[*types.Func]string{},
-		builtins: map[string]string{
-			"fmt.Sprintf":                "printf",
-			"golang.org/x/exp/maps.Keys": "keys",
-			"maps.Keys":                  "keys",
-			"math.Floor":                 "floor",
-			"sort.Strings":               "sortAlpha",
-			"strings.ToLower":            "lower",
-			"strings.ToUpper":            "upper",
-		},
+		root: pkg,
+
+		packages:         mkPkgTree(pkg),
+		namespaces:       map[*types.Package]string{},
+		names:            map[*types.Func]string{},
+		instances:        newInstances(),
+		generics:         map[*ast.FuncDecl]*File{},
+		instantiated:     map[*ast.FuncDecl]bool{},
+		builtins:         map[string]string{},
+		calls:            opts.Calls,
+		diagnose:         opts.Diagnose,
+		annotateFailures: opts.AnnotateFailures,
 	}
-
-	return t.Transpile(), nil
 }
 
 type Transpiler struct {
@@ -85,11 +271,15 @@ type Transpiler struct {
 	Files     []*ast.File
 	TypesInfo *types.Info
 
-	// builtins is a pre-populated cache of function id (fmt.Printf,
-	// github.com/my/pkg.Function) to an equivalent go template / sprig
-	// builtin. Functions may add a +gotohelm:builtin=blah directive to declare
-	// their builtin equivalent.
+	// builtins is a cache of function id (github.com/my/pkg.Function) to the
+	// go template / sprig builtin declared by the function's
+	// +gotohelm:builtin=blah directive, if any. See `builtinFor`.
 	builtins map[string]string
+	// root is the chart's package, which Package is a helper package of, if
+	// it's not the chart's package itself.
+	root *packages.Package
+	// calls are the [CallFunc]s of [TranspileOptions.Calls]. See `callFor`.
+	calls    map[string]CallFunc
 	packages map[string]*packages.Package
 	// namespaces is a cache for holding the namespace package directive. It's
 	// exclusively used by `namespaceFor`.
@@ -97,6 +287,45 @@ type Transpiler struct {
 	// namespaces is a cache for holding the transpiled name of a function.
 	// It's exclusively used by `funcNameFor`.
 	names map[*types.Func]string
+	// tmpCount is a counter used to generate unique names for temporary
+	// variables. It's exclusively used by `tmpVar`.
+	tmpCount int
+	// diagnose indicates that unsupported nodes should be collected into
+	// diagnostics rather than aborting transpilation.
+	diagnose    bool
+	diagnostics Diagnostics
+	// closures collects the [Func]s synthesized from the function literals
+	// within the function declaration being transpiled. It's exclusively used
+	// by `transpileFuncLit`.
+	closures []*Func
+	// funcBody is the body of the function declaration being transpiled.
+	funcBody *funcBody
+	// results are the results of the function being transpiled, which
+	// returned values are converted to. See `convert`.
+	results *types.Tuple
+	// returned is the flag set by return statements within loops of the
+	// function being transpiled. It's exclusively used by `transpileLoop`.
+	returned *Ident
+	// instances is the set of generic function instances required by the
+	// chart. It's shared with the Transpilers of helper packages.
+	instances *instances
+	// instance is the generic function instance currently being
+	// transpiled, if any. See `subst`.
+	instance *instance
+	// generics maps the generic functions of this package to the [File]
+	// that their instances are to be transpiled into.
+	generics map[*ast.FuncDecl]*File
+	// instantiated records the generic functions that have been
+	// transpiled at least once. It's exclusively used by
+	// `diagnoseGenerics`.
+	instantiated map[*ast.FuncDecl]bool
+	// annotateFailures indicates that failure messages should include the
+	// source of the failure. It's exclusively used by `annotateFailure`.
+	annotateFailures bool
+	// pass is the [analysis.Pass] being run when transpiling from within
+	// [Analyzer]. It's used to import facts about dependencies, as their
+	// syntax is not available.
+	pass *analysis.Pass
 }
 
 func (t *Transpiler) Transpile() *Chart {
@@ -107,13 +336,49 @@ func (t *Transpiler) Transpile() *Chart {
 		}
 	}
 
+	t.transpileInstances()
+
+	if t.diagnose {
+		t.diagnoseGenerics()
+	}
+
 	// Finally, include the shims file with all transpiled charts.
-	// NB: When the bootstrap package is transpiled shims is nil.
+	// NB: When the bootstrap package is transpiled or when running as an
+	// [Analyzer] shims is nil.
 	chart.Files = append(chart.Files, shims)
 
 	return &chart
 }
 
+// transpileHelper transpiles all files of a helper package into a single
+// `_<namespace>.tpl` [File].
+func (t *Transpiler) transpileHelper() *File {
+	file := &File{
+		Name:   fmt.Sprintf("_%s.tpl", t.namespaceFor(t.Package.Types)),
+		Source: t.Package.PkgPath,
+	}
+
+	for _, f := range t.Files {
+		if transpiled := t.transpileFile(f); transpiled != nil {
+			file.Funcs = append(file.Funcs, transpiled.Funcs...)
+		}
+	}
+
+	// Instances of generic functions are all transpiled into the single
+	// helper file.
+	for decl := range t.generics {
+		t.generics[decl] = file
+	}
+
+	t.transpileInstances()
+
+	if t.diagnose {
+		t.diagnoseGenerics()
+	}
+
+	return file
+}
+
 func (t *Transpiler) transpileFile(f *ast.File) *File {
 	path := t.Fset.File(f.Pos()).Name()
 	source := filepath.Base(path)
@@ -133,8 +398,30 @@ func (t *Transpiler) transpileFile(f *ast.File) *File {
 		return nil
 	}
 
-	var funcs []*Func
+	file := &File{
+		Name:   name,
+		Source: source,
+	}
+
 	for _, d := range f.Decls {
+		if gen, ok := d.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				file.Funcs = append(file.Funcs, t.transpileInterface(spec.(*ast.TypeSpec))...)
+			}
+			continue
+		}
+
+		if gen, ok := d.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+			if v, ok := parseDirectives(gen.Doc.Text())["ignore"]; ok && v == "true" {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				file.Funcs = append(file.Funcs, t.transpileVarSpec(spec.(*ast.ValueSpec))...)
+			}
+			continue
+		}
+
 		fn, ok := d.(*ast.FuncDecl)
 		if !ok {
 			continue
@@ -145,43 +432,169 @@ func (t *Transpiler) transpileFile(f *ast.File) *File {
 			continue
 		}
 
-		var params []Node
-		if fn.Recv != nil {
-			for _, param := range fn.Recv.List {
-				for _, name := range param.Names {
-					params = append(params, t.transpileExpr(name))
-				}
-			}
+		// Generic functions are transpiled once per instance, after all
+		// instances are known. See `transpileInstances`.
+		if isGeneric(fn) {
+			t.generics[fn] = file
+			continue
+		}
+
+		file.Funcs = append(file.Funcs, t.transpileFuncDecls(fn)...)
+	}
+
+	return file
+}
+
+// transpileFuncDecls returns the transpiled function declaration followed by
+// the Funcs synthesized from any function literals therein.
+func (t *Transpiler) transpileFuncDecls(fn *ast.FuncDecl) []*Func {
+	var funcs []*Func
+	if transpiled := t.transpileFuncDecl(fn); transpiled != nil {
+		funcs = append(funcs, transpiled)
+	}
+
+	for _, closure := range t.closures {
+		if closure != nil {
+			funcs = append(funcs, closure)
 		}
+	}
+
+	return funcs
+}
 
-		for _, param := range fn.Type.Params.List {
+func (t *Transpiler) transpileFuncDecl(fn *ast.FuncDecl) (_ *Func) {
+	t.closures = nil
+	t.funcBody = &funcBody{Body: fn.Body, Info: t.TypesInfo}
+	t.results = t.TypesInfo.Defs[fn.Name].Type().(*types.Signature).Results()
+	defer func() { t.funcBody, t.results = nil, nil }()
+
+	defer t.recoverUnsupported(fn)
+
+	var params []Node
+	if fn.Recv != nil {
+		for _, param := range fn.Recv.List {
 			for _, name := range param.Names {
 				params = append(params, t.transpileExpr(name))
 			}
 		}
+	}
 
-		var statements []Node
-		for _, stmt := range fn.Body.List {
-			statements = append(statements, t.transpileStatement(stmt))
+	for _, param := range fn.Type.Params.List {
+		for _, name := range param.Names {
+			params = append(params, t.transpileExpr(name))
+		}
+	}
+
+	var statements []Node
+	for _, stmt := range fn.Body.List {
+		statements = append(statements, t.transpileStatement(stmt))
+	}
+
+	return &Func{
+		Name:       t.declNameFor(fn),
+		Namespace:  t.namespaceFor(t.Package.Types),
+		Source:     t.Fset.PositionFor(fn.Pos(), true),
+		Params:     params,
+		Statements: statements,
+	}
+}
+
+// transpileVarSpec transpiles package level variables into functions, of no
+// arguments, that return their initial value. Templates have no global state
+// so references to package level variables are calls of said functions and
+// the variables themselves may not be assigned to.
+func (t *Transpiler) transpileVarSpec(spec *ast.ValueSpec) (_ []*Func) {
+	defer t.recoverUnsupported(spec)
+
+	if len(spec.Values) > 0 && len(spec.Values) != len(spec.Names) {
+		panic(&Unsupported{
+			Node: spec,
+			Fset: t.Fset,
+			Msg:  "package level variables must be initialized with a single value each",
+		})
+	}
+
+	var funcs []*Func
+	for i, name := range spec.Names {
+		if name.Name == "_" {
+			continue
+		}
+
+		t.closures = nil
+
+		value := t.zeroOf(t.TypesInfo.Defs[name].Type())
+		if len(spec.Values) > 0 {
+			value = t.convert(spec.Values[i], t.TypesInfo.Defs[name].Type(), t.transpileExpr(spec.Values[i]))
 		}
 
-		// TODO add a source field here? Ideally with a line number.
 		funcs = append(funcs, &Func{
-			Name:       t.funcNameFor(t.Package.TypesInfo.ObjectOf(fn.Name).(*types.Func)),
+			Name:       name.Name,
 			Namespace:  t.namespaceFor(t.Package.Types),
-			Params:     params,
-			Statements: statements,
+			Source:     t.Fset.PositionFor(name.Pos(), true),
+			Statements: []Node{&Return{Expr: value}},
 		})
+
+		for _, closure := range t.closures {
+			if closure != nil {
+				funcs = append(funcs, closure)
+			}
+		}
 	}
 
-	return &File{
-		Name:   name,
-		Source: source,
-		Funcs:  funcs,
+	return funcs
+}
+
+// recoverUnsupported is deferred by the various transpile methods to convert
+// any panics into an [Unsupported] that references the node being
+// transpiled. Any panic that's not already an [Unsupported] is attributed to
+// the innermost node.
+// If diagnostics are enabled, the [Unsupported] is recorded and the panic is
+// swallowed, causing the deferring function to return its zero value.
+// Otherwise, the [Unsupported] is re-panicked to abort transpilation.
+func (t *Transpiler) recoverUnsupported(n ast.Node) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	u, ok := r.(*Unsupported)
+	if !ok {
+		// Panics that aren't Unsupported are likely to be a knock on effect
+		// of a previously recorded diagnostic (e.g. an unexpected nil Node).
+		// Don't report them twice.
+		for _, d := range t.diagnostics {
+			if d.Node.Pos() >= n.Pos() && d.Node.End() <= n.End() {
+				return
+			}
+		}
+
+		u = &Unsupported{Node: n, Fset: t.Fset, Msg: fmt.Sprint(r)}
+	}
+
+	if !t.diagnose {
+		panic(u)
 	}
+
+	t.diagnostics = append(t.diagnostics, u)
 }
 
+// transpileStatement transpiles the given statement and annotates the result
+// with its source position for the purpose of generating [SourceMap]s.
 func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
+	node := t.transpileStmt(stmt)
+
+	// Nodes introduced by AST rewrites don't have a position. They'll be
+	// attributed to their nearest sourced parent.
+	if node == nil || !stmt.Pos().IsValid() {
+		return node
+	}
+
+	return &Sourced{Node: node, Pos: t.Fset.PositionFor(stmt.Pos(), true)}
+}
+
+func (t *Transpiler) transpileStmt(stmt ast.Stmt) (_ Node) {
+	defer t.recoverUnsupported(stmt)
+
 	switch stmt := stmt.(type) {
 	case nil:
 		return nil
@@ -210,7 +623,8 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 
 			rhs := t.zeroOf(t.TypesInfo.TypeOf(spec.Names[0]))
 			if len(spec.Values) > 0 {
-				rhs = t.transpileExpr(spec.Values[0])
+				rhs = t.copyValue(spec.Values[0], t.TypesInfo.TypeOf(spec.Values[0]), t.transpileExpr(spec.Values[0]), rootVar(t.TypesInfo, spec.Names[0]), t.funcBody)
+				rhs = t.convert(spec.Values[0], t.TypesInfo.TypeOf(spec.Names[0]), rhs)
 			}
 
 			return &Assignment{
@@ -220,7 +634,11 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 			}
 
 		default:
-			panic(fmt.Sprintf("unsupported declaration: %#v", d))
+			panic(&Unsupported{
+				Node: d,
+				Fset: t.Fset,
+				Msg:  "unsupported declaration",
+			})
 		}
 
 	case *ast.BranchStmt:
@@ -233,17 +651,32 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 		}
 
 	case *ast.ReturnStmt:
+		var ret Node
 		if len(stmt.Results) == 1 {
-			return &Return{Expr: t.transpileExpr(stmt.Results[0])}
+			ret = &Return{Expr: t.convertResult(stmt, 0)}
+		} else {
+			var results []Node
+			for i := range stmt.Results {
+				results = append(results, t.convertResult(stmt, i))
+			}
+			ret = &Return{Expr: &BuiltInCall{FuncName: "list", Arguments: results}}
 		}
 
-		var results []Node
-		for _, r := range stmt.Results {
-			results = append(results, t.transpileExpr(r))
+		// Returns from within loops only break out of the innermost loop.
+		// Flag that the function has returned. See `transpileLoop`.
+		if t.returned != nil {
+			return &Block{Statements: []Node{
+				&Assignment{LHS: t.returned, RHS: &Literal{Value: "true"}},
+				ret,
+			}}
 		}
-		return &Return{Expr: &BuiltInCall{FuncName: "list", Arguments: results}}
+		return ret
 
 	case *ast.AssignStmt:
+		for _, lhs := range stmt.Lhs {
+			t.checkAssignable(lhs)
+		}
+
 		if len(stmt.Lhs) != len(stmt.Rhs) {
 			break
 		}
@@ -261,8 +694,11 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 		}
 
 		// +=, /=, *=, etc show up as assignments. They're not supported in
-		// templates. We'll need to either rewrite the expression here or add
-		// another AST rewrite.
+		// templates so they're expanded into their long form.
+		if op := assignOp(stmt.Tok); op != token.ILLEGAL {
+			return t.transpileAssignOp(stmt, stmt.Tok, stmt.Lhs[0], op, t.typeOf(stmt.Rhs[0]), t.transpileExpr(stmt.Rhs[0]))
+		}
+
 		switch stmt.Tok {
 		case token.ASSIGN, token.DEFINE:
 		default:
@@ -275,8 +711,14 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 
 		// TODO could simplify this by performing a type switch on the
 		// transpiled result of lhs.
+		rhs := t.transpileExpr(stmt.Rhs[0])
+		if !isBlank(stmt.Lhs[0]) {
+			rhs = t.copyValue(stmt.Rhs[0], t.TypesInfo.TypeOf(stmt.Rhs[0]), rhs, rootVar(t.TypesInfo, stmt.Lhs[0]), t.funcBody)
+			rhs = t.convert(stmt.Rhs[0], t.TypesInfo.TypeOf(stmt.Lhs[0]), rhs)
+		}
+
 		if _, ok := stmt.Lhs[0].(*ast.SelectorExpr); ok {
-			selector := t.transpileExpr(stmt.Lhs[0]).(*Selector)
+			selector := asSelector(t.transpileExpr(stmt.Lhs[0]))
 
 			return &Statement{
 				Expr: &BuiltInCall{
@@ -284,7 +726,7 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 					Arguments: []Node{
 						selector.Expr,
 						&Literal{Value: strconv.Quote(selector.Field)},
-						t.transpileExpr(stmt.Rhs[0]),
+						rhs,
 					},
 				},
 			}
@@ -293,32 +735,51 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 		// TODO could simplify this by implementing an IndexExpr node and then
 		// performing a type switch on the transpiled result of lhs.
 		if idx, ok := stmt.Lhs[0].(*ast.IndexExpr); ok {
+			if !t.isMap(idx.X) {
+				set := &Call{FuncName: "_shims.slice_Set", Arguments: []Node{t.transpileExpr(idx.X), t.transpileIndex(idx.X, idx.Index), rhs}}
+				return t.assignBack(idx.X, set)
+			}
+
 			return &Statement{
 				Expr: &BuiltInCall{
 					FuncName: "set",
 					Arguments: []Node{
 						t.transpileExpr(idx.X),
-						t.transpileExpr(idx.Index),
-						t.transpileExpr(stmt.Rhs[0]),
+						t.transpileIndex(idx.X, idx.Index),
+						rhs,
 					},
 				},
 			}
 		}
 
-		rhs := t.transpileExpr(stmt.Rhs[0])
 		lhs := t.transpileExpr(stmt.Lhs[0])
 
 		return &Assignment{RHS: rhs, LHS: lhs, New: stmt.Tok.String() == ":="}
 
-	case *ast.RangeStmt:
-		return &Range{
-			Key:   t.transpileExpr(stmt.Key),
-			Value: t.transpileExpr(stmt.Value),
-			Over:  t.transpileExpr(stmt.X),
-			Body:  t.transpileStatement(stmt.Body),
+	case *ast.IncDecStmt:
+		t.checkAssignable(stmt.X)
+
+		// ++ and -- are expanded into their long form, just as +=.
+		typ := t.typeOf(stmt.X)
+		one := t.maybeCast(&Literal{Value: "1"}, typ)
+
+		op := token.ADD
+		if stmt.Tok == token.DEC {
+			op = token.SUB
 		}
 
+		return t.transpileAssignOp(stmt, stmt.Tok, stmt.X, op, typ, one)
+
+	case *ast.RangeStmt:
+		return t.transpileLoop(stmt.Body, func(body Node) Node {
+			return t.transpileRangeStmt(stmt, body)
+		})
+
 	case *ast.ExprStmt:
+		if call, ok := stmt.X.(*ast.CallExpr); ok && t.modifiesInPlace(call) {
+			return t.transpileInPlace(call)
+		}
+
 		return &Statement{
 			Expr: t.transpileExpr(stmt.X),
 		}
@@ -337,109 +798,20 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 			Body: t.transpileStatement(stmt.Body),
 			Else: t.transpileStatement(stmt.Else),
 		}
-	case *ast.ForStmt:
-		var start, stop Node
-		if b, ok := stmt.Cond.(*ast.BinaryExpr); ok {
-			switch b.Op {
-			case token.LSS, token.LEQ:
-				if _, ok := b.X.(*ast.SelectorExpr); ok {
-					start = t.transpileExpr(b.X)
-				} else {
-					switch declaration := b.X.(*ast.Ident).Obj.Decl.(type) {
-					case *ast.AssignStmt:
-						start = t.transpileExpr(declaration.Rhs[0])
-					case *ast.Field:
-						start = t.transpileExpr(declaration.Names[0])
-					}
-				}
 
-				if _, ok := b.Y.(*ast.SelectorExpr); ok {
-					stop = t.transpileExpr(b.Y)
-				} else {
-					switch declaration := b.Y.(*ast.Ident).Obj.Decl.(type) {
-					case *ast.AssignStmt:
-						stop = t.transpileExpr(declaration.Rhs[0])
-					case *ast.Field:
-						stop = t.transpileExpr(declaration.Names[0])
-					}
-				}
-			case token.GTR, token.GEQ:
-				if _, ok := b.X.(*ast.SelectorExpr); ok {
-					stop = t.transpileExpr(b.X)
-				} else {
-					switch declaration := b.X.(*ast.Ident).Obj.Decl.(type) {
-					case *ast.AssignStmt:
-						stop = t.transpileExpr(declaration.Rhs[0])
-					case *ast.Field:
-						stop = t.transpileExpr(declaration.Names[0])
-					}
-				}
+	case *ast.SwitchStmt:
+		return t.transpileSwitchStmt(stmt)
 
-				if _, ok := b.Y.(*ast.SelectorExpr); ok {
-					start = t.transpileExpr(b.Y)
-				} else {
-					switch declaration := b.Y.(*ast.Ident).Obj.Decl.(type) {
-					case *ast.AssignStmt:
-						start = t.transpileExpr(declaration.Rhs[0])
-					case *ast.Field:
-						start = t.transpileExpr(declaration.Names[0])
-					}
-				}
-			default:
-				panic(&Unsupported{
-					Node: stmt,
-					Fset: t.Fset,
-					Msg:  fmt.Sprintf("%T of %s is not supported in for condition", b, b.Op),
-				})
-			}
-		}
+	case *ast.TypeSwitchStmt:
+		return t.transpileTypeSwitchStmt(stmt)
 
-		var step Node
-		switch p := stmt.Post.(type) {
-		case *ast.AssignStmt:
-			if b, ok := p.Rhs[0].(*ast.BasicLit); ok && p.Tok == token.SUB_ASSIGN {
-				b.Value = fmt.Sprintf("-%s", b.Value)
-				// switch start with stop expression as step is decreasing
-				step = start
-				start = stop
-				stop = step
-
-				step = t.transpileExpr(b)
-			} else {
-				step = t.transpileExpr(b)
-			}
-		case *ast.IncDecStmt:
-			switch p.Tok {
-			case token.INC:
-				step = &Literal{Value: "1"}
-			case token.DEC:
-				step = &Literal{Value: "-1"}
+	case *ast.ForStmt:
+		return t.transpileLoop(stmt.Body, func(body Node) Node {
+			if loop := t.transpileCountingLoop(stmt, body); loop != nil {
+				return loop
 			}
-		default:
-			panic(&Unsupported{
-				Node: stmt,
-				Fset: t.Fset,
-				Msg:  "unhandled ast.ForStmt",
-			})
-		}
-
-		if stop == nil || start == nil || step == nil {
-			panic(&Unsupported{
-				Node: stmt,
-				Fset: t.Fset,
-				Msg:  fmt.Sprintf("start: %v; stop: %v; step: %v", start, stop, step),
-			})
-		}
-		return &Range{
-			Key:   &Ident{Name: "_"},
-			Value: &Literal{Value: fmt.Sprintf("$%s", stmt.Init.(*ast.AssignStmt).Lhs[0].(*ast.Ident).Name)},
-			Over: &UntilStep{
-				Start: start,
-				Stop:  stop,
-				Step:  step,
-			},
-			Body: t.transpileStatement(stmt.Body),
-		}
+			return t.transpileForLoop(stmt, body)
+		})
 	}
 
 	panic(&Unsupported{
@@ -449,34 +821,1156 @@ func (t *Transpiler) transpileStatement(stmt ast.Stmt) Node {
 	})
 }
 
-func (t *Transpiler) transpileExpr(n ast.Expr) Node {
-	switch n := n.(type) {
-	case nil:
-		return nil
+// modifiesInPlace returns true if call is a call of one of the functions in
+// [inPlace].
+func (t *Transpiler) modifiesInPlace(call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(t.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Type().(*types.Signature).Recv() != nil {
+		return false
+	}
+	return inPlace[fn.Pkg().Path()+"."+fn.Name()]
+}
 
-	case *ast.BasicLit:
-		return t.maybeCast(&Literal{Value: n.Value}, t.typeOf(n))
+// transpileInPlace transpiles call, a call of a function that modifies its
+// first argument in place, into an assignment of its result to said argument.
+// e.g. `slices.Sort(x)` is `$x = (get (fromJson (include "_shims.slices_Sort" ...`
+func (t *Transpiler) transpileInPlace(call *ast.CallExpr) Node {
+	return t.assignBack(call.Args[0], t.transpileExpr(call))
+}
 
-	case *ast.ParenExpr:
-		return &ParenExpr{Expr: t.transpileExpr(n.X)}
+// assignBack transpiles the assignment of value, a modified copy of the list
+// e, back to e. Lists can't be modified in place so this is how in place
+// modifications of slices are emulated. Other references to the list are
+// left unmodified.
+func (t *Transpiler) assignBack(e ast.Expr, value Node) Node {
+	t.checkAssignable(e)
 
-	case *ast.StarExpr:
-		// TODO this should be wrapped in something like "Assert not nil"
-		return t.transpileExpr(n.X)
+	switch lhs := e.(type) {
+	case *ast.Ident:
+		return &Assignment{LHS: t.transpileExpr(lhs), RHS: value}
 
-	case *ast.SliceExpr:
-		target := t.transpileExpr(n.X)
-		low := t.transpileExpr(n.Low)
-		high := t.transpileExpr(n.High)
-		max := t.transpileExpr(n.Max)
+	case *ast.SelectorExpr:
+		selector := asSelector(t.transpileExpr(lhs))
 
-		// If low isn't specified it defaults to zero
-		if low == nil {
-			low = &Literal{Value: "0"}
+		return &Statement{
+			Expr: &BuiltInCall{
+				FuncName: "set",
+				Arguments: []Node{
+					selector.Expr,
+					&Literal{Value: strconv.Quote(selector.Field)},
+					value,
+				},
+			},
 		}
 
-		// The builtin `slice` function from go would work great here but sprig
-		// overwrites it for some reason with a worse version.
+	case *ast.IndexExpr:
+		if t.isMap(lhs.X) {
+			return &Statement{
+				Expr: &BuiltInCall{
+					FuncName:  "set",
+					Arguments: []Node{t.transpileExpr(lhs.X), t.transpileIndex(lhs.X, lhs.Index), value},
+				},
+			}
+		}
+	}
+
+	panic(&Unsupported{
+		Node:        e,
+		Fset:        t.Fset,
+		Msg:         fmt.Sprintf("slices may only be modified in place if they are variables, fields, or map values. Got %T", e),
+		Alternative: "a local variable",
+	})
+}
+
+// isMap returns true if e is a map.
+func (t *Transpiler) isMap(e ast.Expr) bool {
+	_, ok := t.typeOf(e).Underlying().(*types.Map)
+	return ok
+}
+
+// transpileAssignOp transpiles assignment operations (e.g. `x += y`, or
+// `x++` as tok), of the already transpiled rhs of type rhsType, as if they
+// were written in their long form, `x = x + y`, using the same typed
+// arithmetic as binary expressions. As in go, the operands of selector and
+// index expressions on the left hand side are evaluated only once.
+func (t *Transpiler) transpileAssignOp(stmt ast.Stmt, tok token.Token, lhs ast.Expr, op token.Token, rhsType types.Type, rhs Node) Node {
+	value := func(current Node) Node {
+		return t.transpileBinaryOp(stmt, op, t.typeOf(lhs), rhsType, current, rhs)
+	}
+
+	var stmts []Node
+
+	once := func(e ast.Expr, transpiled Node) Node {
+		if !hasCall(e) {
+			return transpiled
+		}
+		tmp := t.tmpVar("assignop")
+		stmts = append(stmts, &Assignment{LHS: tmp, New: true, RHS: transpiled})
+		return tmp
+	}
+
+	switch lhs := lhs.(type) {
+	case *ast.Ident:
+		ident := t.transpileExpr(lhs)
+		return &Assignment{LHS: ident, RHS: value(ident)}
+
+	case *ast.SelectorExpr:
+		current := t.transpileExpr(lhs)
+		selector := asSelector(current)
+		selector.Expr = once(lhs.X, selector.Expr)
+
+		stmts = append(stmts, &Statement{
+			Expr: &BuiltInCall{
+				FuncName: "set",
+				Arguments: []Node{
+					selector.Expr,
+					&Literal{Value: strconv.Quote(selector.Field)},
+					value(current),
+				},
+			},
+		})
+
+		return &Block{Statements: stmts}
+
+	case *ast.IndexExpr:
+		x := once(lhs.X, t.transpileExpr(lhs.X))
+		key := once(lhs.Index, t.transpileIndex(lhs.X, lhs.Index))
+
+		if !t.isMap(lhs.X) {
+			current := &BuiltInCall{FuncName: "index", Arguments: []Node{x, key}}
+			set := &Call{FuncName: "_shims.slice_Set", Arguments: []Node{x, key, value(current)}}
+			stmts = append(stmts, t.assignBack(lhs.X, set))
+			return &Block{Statements: stmts}
+		}
+
+		// Missing keys of maps are their zero value in go but nil in
+		// templates.
+		current := &BuiltInCall{
+			FuncName: "default",
+			Arguments: []Node{
+				t.zeroOf(t.typeOf(lhs)),
+				&BuiltInCall{FuncName: "index", Arguments: []Node{x, key}},
+			},
+		}
+
+		stmts = append(stmts, &Statement{
+			Expr: &BuiltInCall{
+				FuncName:  "set",
+				Arguments: []Node{x, key, value(current)},
+			},
+		})
+
+		return &Block{Statements: stmts}
+	}
+
+	panic(&Unsupported{
+		Node: stmt,
+		Fset: t.Fset,
+		Msg:  fmt.Sprintf("%s is not supported on %T", tok, lhs),
+	})
+}
+
+// concat returns the concatenation of the strings a and b. No support for
+// easy string concatenation in helm/sprig/templates soooo. Printf. Chains of
+// concatenations (a + b + c) are flattened into a single call to printf.
+func concat(a, b Node) Node {
+	var args []Node
+	for _, n := range []Node{a, b} {
+		if operands, ok := concatOperands(n); ok {
+			args = append(args, operands...)
+		} else {
+			args = append(args, n)
+		}
+	}
+
+	format := &Literal{Value: strconv.Quote(strings.Repeat("%s", len(args)))}
+
+	return &BuiltInCall{FuncName: "printf", Arguments: append([]Node{format}, args...)}
+}
+
+// concatOperands returns the operands of n if it's a concatenation, as
+// returned by [concat].
+func concatOperands(n Node) ([]Node, bool) {
+	if paren, ok := n.(*ParenExpr); ok {
+		n = paren.Expr
+	}
+
+	call, ok := n.(*BuiltInCall)
+	if !ok || call.FuncName != "printf" || len(call.Arguments) < 2 {
+		return nil, false
+	}
+
+	format, ok := call.Arguments[0].(*Literal)
+	if !ok || format.Value != strconv.Quote(strings.Repeat("%s", len(call.Arguments)-1)) {
+		return nil, false
+	}
+
+	return call.Arguments[1:], true
+}
+
+// isStringType returns true if typ is any string type. e.g. string, untyped
+// string, or a named string type.
+func isStringType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// asSelector returns the [Selector] of a transpiled [ast.SelectorExpr].
+// Selectors of numeric fields are wrapped in a [Cast].
+func asSelector(n Node) *Selector {
+	if cast, ok := n.(*Cast); ok {
+		n = cast.X
+	}
+	return n.(*Selector)
+}
+
+// assignOp returns the binary operator of the assignment operation tok
+// (e.g. + for +=) or [token.ILLEGAL] if tok is not an assignment operation.
+func assignOp(tok token.Token) token.Token {
+	if token.ADD_ASSIGN <= tok && tok <= token.AND_NOT_ASSIGN {
+		return tok + (token.ADD - token.ADD_ASSIGN)
+	}
+	return token.ILLEGAL
+}
+
+// hasCall returns true if the expression e contains any function calls and
+// therefore may have side effects.
+func hasCall(e ast.Expr) bool {
+	found := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		if _, ok := n.(*ast.CallExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// transpileBinaryOp transpiles the binary operation `x op y`, where x and y
+// have already been transpiled, into the equivalent template function. The
+// types of x and y determine which function is used. n is the node
+// responsible for the operation, either an [ast.BinaryExpr] or an
+// [ast.AssignStmt] (e.g. +=).
+func (t *Transpiler) transpileBinaryOp(n ast.Node, op token.Token, xType, yType types.Type, x, y Node) Node {
+	untyped := [3]string{"_", op.String(), "_"}
+	typed := [3]string{xType.String(), op.String(), yType.String()}
+
+	f := func(op string) func(a, b Node) Node {
+		return func(a, b Node) Node {
+			return &BuiltInCall{FuncName: op, Arguments: []Node{a, b}}
+		}
+	}
+
+	wrapWithCast := func(op, cast string) func(a, b Node) Node {
+		return func(a, b Node) Node {
+			return &Cast{To: cast, X: &BuiltInCall{FuncName: op, Arguments: []Node{a, b}}}
+		}
+	}
+
+	// Operations without a sprig equivalent are implemented in _shims.
+	shimWithCast := func(op, cast string) func(a, b Node) Node {
+		return func(a, b Node) Node {
+			return &Cast{To: cast, X: &Call{FuncName: "_shims." + op, Arguments: []Node{a, b}}}
+		}
+	}
+
+	// Poor man's pattern matching :[
+	mapping := map[[3]string]func(a, b Node) Node{
+		{"_", token.EQL.String(), "_"}:  f("eq"),
+		{"_", token.NEQ.String(), "_"}:  f("ne"),
+		{"_", token.LAND.String(), "_"}: f("and"),
+		{"_", token.LOR.String(), "_"}:  f("or"),
+		{"_", token.GTR.String(), "_"}:  f("gt"),
+		{"_", token.LSS.String(), "_"}:  f("lt"),
+		{"_", token.GEQ.String(), "_"}:  f("ge"),
+		{"_", token.LEQ.String(), "_"}:  f("le"),
+
+		{"float32", token.ADD.String(), "float32"}: wrapWithCast("addf", "float64"),
+		{"float32", token.MUL.String(), "float32"}: wrapWithCast("mulf", "float64"),
+		{"float32", token.QUO.String(), "float32"}: wrapWithCast("divf", "float32"),
+		{"float32", token.SUB.String(), "float32"}: wrapWithCast("subf", "float64"),
+
+		{"float64", token.ADD.String(), "float64"}: wrapWithCast("addf", "float64"),
+		{"float64", token.MUL.String(), "float64"}: wrapWithCast("mulf", "float64"),
+		{"float64", token.QUO.String(), "float64"}: wrapWithCast("divf", "float64"),
+		{"float64", token.SUB.String(), "float64"}: wrapWithCast("subf", "float64"),
+
+		{"int", token.ADD.String(), "int"}: wrapWithCast("add", "int"),
+		{"int", token.MUL.String(), "int"}: wrapWithCast("mul", "int"),
+		{"int", token.QUO.String(), "int"}: wrapWithCast("div", "int"),
+		{"int", token.REM.String(), "int"}: wrapWithCast("mod", "int"),
+		{"int", token.SUB.String(), "int"}: wrapWithCast("sub", "int"),
+		{"int", token.OR.String(), "int"}:  shimWithCast("bitwiseor", "int"),
+
+		{"int32", token.ADD.String(), "int32"}: wrapWithCast("add", "int"),
+		{"int32", token.MUL.String(), "int32"}: wrapWithCast("mul", "int"),
+		{"int32", token.QUO.String(), "int32"}: wrapWithCast("div", "int"),
+		{"int32", token.REM.String(), "int32"}: wrapWithCast("mod", "int"),
+		{"int32", token.SUB.String(), "int32"}: wrapWithCast("sub", "int"),
+		{"int32", token.OR.String(), "int32"}:  shimWithCast("bitwiseor", "int"),
+
+		{"int64", token.ADD.String(), "int64"}: wrapWithCast("add", "int64"),
+		{"int64", token.MUL.String(), "int64"}: wrapWithCast("mul", "int64"),
+		{"int64", token.QUO.String(), "int64"}: wrapWithCast("div", "int64"),
+		{"int64", token.REM.String(), "int64"}: wrapWithCast("mod", "int64"),
+		{"int64", token.SUB.String(), "int64"}: wrapWithCast("sub", "int64"),
+		{"int64", token.OR.String(), "int64"}:  shimWithCast("bitwiseor", "int64"),
+
+		{"untyped int", token.ADD.String(), "untyped int"}: f("add"),
+		{"untyped int", token.MUL.String(), "untyped int"}: f("mul"),
+		{"untyped int", token.QUO.String(), "untyped int"}: f("div"),
+		{"untyped int", token.REM.String(), "untyped int"}: f("mod"),
+		{"untyped int", token.SUB.String(), "untyped int"}: f("sub"),
+
+		{"untyped float", token.ADD.String(), "untyped float"}: f("addf"),
+		{"untyped float", token.MUL.String(), "untyped float"}: f("mulf"),
+		{"untyped float", token.QUO.String(), "untyped float"}: f("divf"),
+		{"untyped float", token.SUB.String(), "untyped float"}: f("subf"),
+	}
+
+	if op == token.EQL || op == token.NEQ {
+		if eq := t.transpileEquality(n, op, xType, yType, x, y); eq != nil {
+			return eq
+		}
+	}
+
+	// String concatenation applies to all string types, including untyped
+	// and named strings, so it can't be expressed through mapping.
+	if op == token.ADD && isStringType(xType) && isStringType(yType) {
+		return concat(x, y)
+	}
+
+	// Typed versions take precedence.
+	if funcName, ok := mapping[typed]; ok {
+		return funcName(x, y)
+	}
+
+	// Fallback to "wild cards" (_).
+	if funcName, ok := mapping[untyped]; ok {
+		return funcName(x, y)
+	}
+
+	panic(&Unsupported{
+		Node: n,
+		Fset: t.Fset,
+		Msg:  fmt.Sprintf(`No matching %T signature for %v or %v`, n, typed, untyped),
+	})
+}
+
+// transpileEquality transpiles `x == y` or `x != y` for operands that `eq`
+// can't compare. It returns nil if `eq` and `ne` suffice.
+//
+// `eq` errors upon comparing dicts, lists, or values of differing kinds, so
+// structs, arrays, and interfaces (which may hold any of those) are compared
+// by their JSON representations. `deepEqual` isn't used as it distinguishes
+// the ints and float64s that result from JSON round trips.
+func (t *Transpiler) transpileEquality(n ast.Node, op token.Token, xType, yType types.Type, x, y Node) Node {
+	xType, yType = t.subst(xType), t.subst(yType)
+
+	// Any type that may be nil may be compared to nil, which `eq` handles.
+	if isUntypedNil(xType) || isUntypedNil(yType) {
+		return nil
+	}
+
+	// The type checker should reject these but be sure that they don't
+	// silently become a runtime error in templates.
+	if !types.Comparable(xType) || !types.Comparable(yType) {
+		panic(&Unsupported{
+			Node: n,
+			Fset: t.Fset,
+			Msg:  fmt.Sprintf("%v and %v may not be compared", xType, yType),
+		})
+	}
+
+	if !types.AssignableTo(xType, yType) && !types.AssignableTo(yType, xType) {
+		panic(&Unsupported{
+			Node: n,
+			Fset: t.Fset,
+			Msg:  fmt.Sprintf("mismatched types %v and %v may not be compared", xType, yType),
+		})
+	}
+
+	// Pointers to basic types are transpiled into the values they point to,
+	// which `eq` compares, but dicts and lists have no notion of identity.
+	if isPointerToComposite(xType) || isPointerToComposite(yType) {
+		panic(&Unsupported{
+			Node:        n,
+			Fset:        t.Fset,
+			Msg:         fmt.Sprintf("pointers to structs and arrays may not be compared. Got %v and %v", xType, yType),
+			Alternative: "`*x == *y`",
+		})
+	}
+
+	if !isDeeplyCompared(xType) && !isDeeplyCompared(yType) {
+		return nil
+	}
+
+	fn := "eq"
+	if op == token.NEQ {
+		fn = "ne"
+	}
+
+	x, y = t.withZeroFields(xType, x), t.withZeroFields(yType, y)
+
+	return &BuiltInCall{
+		FuncName: fn,
+		Arguments: []Node{
+			&BuiltInCall{FuncName: "toJson", Arguments: []Node{x}},
+			&BuiltInCall{FuncName: "toJson", Arguments: []Node{y}},
+		},
+	}
+}
+
+// withZeroFields returns x, a value of typ, merged onto the zero value of typ
+// if it's a struct. Struct literals include every field but dicts that
+// weren't constructed by a literal, such as those of .Values, may omit zero
+// valued fields, which would otherwise be reflected in their JSON
+// representations.
+func (t *Transpiler) withZeroFields(typ types.Type, x Node) Node {
+	if _, ok := typ.Underlying().(*types.Struct); !ok {
+		return x
+	}
+
+	zero, ok := t.zeroOf(typ).(*DictLiteral)
+	if !ok {
+		return x
+	}
+
+	return &BuiltInCall{FuncName: "mustMergeOverwrite", Arguments: []Node{zero, x}}
+}
+
+// isDeeplyCompared returns true if values of typ must be compared by their
+// JSON representations rather than `eq`.
+func isDeeplyCompared(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Struct, *types.Array, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// isPointerToComposite returns true if typ is a pointer to a struct or array.
+func isPointerToComposite(typ types.Type) bool {
+	ptr, ok := typ.Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	switch ptr.Elem().Underlying().(type) {
+	case *types.Struct, *types.Array:
+		return true
+	}
+	return false
+}
+
+// isUntypedNil returns true if typ is the type of the nil literal.
+func isUntypedNil(typ types.Type) bool {
+	basic, ok := typ.(*types.Basic)
+	return ok && basic.Kind() == types.UntypedNil
+}
+
+// transpileRangeStmt transpiles a range statement with the already transpiled
+// body. Ranges over slices, arrays, and maps are equivalent in templates but
+// ranges over integers and strings are not.
+func (t *Transpiler) transpileRangeStmt(stmt *ast.RangeStmt, body Node) Node {
+	basic, ok := t.typeOf(stmt.X).Underlying().(*types.Basic)
+
+	switch {
+	case ok && basic.Info()&types.IsInteger != 0:
+		// `for i := range n` only yields the index, which `until` yields as
+		// the value.
+		return &Range{
+			Value: t.transpileExpr(stmt.Key),
+			Over:  &Until{Expr: t.transpileExpr(stmt.X)},
+			Body:  body,
+		}
+
+	case ok && basic.Info()&types.IsString != 0:
+		// Templates range over the bytes of strings whereas go ranges over
+		// runes and their byte offsets. `_shims.runes` returns pairs of the
+		// latter.
+		char := t.tmpVar("char")
+
+		var statements []Node
+		for i, e := range []ast.Expr{stmt.Key, stmt.Value} {
+			if ident, ok := e.(*ast.Ident); !ok || ident.Name == "_" {
+				continue
+			}
+
+			statements = append(statements, &Assignment{
+				LHS: t.transpileExpr(e),
+				New: stmt.Tok == token.DEFINE,
+				RHS: &Cast{
+					X:  &BuiltInCall{FuncName: "index", Arguments: []Node{char, &Literal{Value: strconv.Itoa(i)}}},
+					To: "int",
+				},
+			})
+		}
+
+		return &Range{
+			Value: char,
+			Over:  &Call{FuncName: "_shims.runes", Arguments: []Node{t.transpileExpr(stmt.X)}},
+			Body:  &Block{Statements: append(statements, body)},
+		}
+	}
+
+	key := t.transpileExpr(stmt.Key)
+
+	// Integer keys of maps are stringified. See transpileMapKey.
+	if m, ok := t.typeOf(stmt.X).Underlying().(*types.Map); ok && isInteger(m.Key()) && key != nil && key.(*Ident).Name != "_" {
+		body = &Block{Statements: []Node{
+			&Assignment{LHS: key, RHS: &Cast{X: key, To: "int"}},
+			body,
+		}}
+	}
+
+	// Values of struct and array elements are copies in go. See copyValue.
+	value := t.transpileExpr(stmt.Value)
+	if ident, ok := stmt.Value.(*ast.Ident); ok && ident.Name != "_" {
+		v := t.TypesInfo.ObjectOf(ident).(*types.Var)
+		if copied := t.copyValue(ident, v.Type(), value, v, t.funcBody); copied != value {
+			body = &Block{Statements: []Node{
+				&Assignment{LHS: value, RHS: copied},
+				body,
+			}}
+		}
+	}
+
+	return &Range{
+		Key:   key,
+		Value: value,
+		Over:  t.transpileExpr(stmt.X),
+		Body:  body,
+	}
+}
+
+// transpileIndex transpiles the index of an index expression on x, which may
+// be a map key.
+func (t *Transpiler) transpileIndex(x, index ast.Expr) Node {
+	if _, ok := t.typeOf(x).Underlying().(*types.Map); ok {
+		return t.transpileMapKey(index)
+	}
+	return t.transpileExpr(index)
+}
+
+// isZeroConst returns true if e is nil or a constant zero value.
+func (t *Transpiler) isZeroConst(e ast.Expr) bool {
+	tv := t.TypesInfo.Types[e]
+	if tv.IsNil() {
+		return true
+	}
+
+	if tv.Value == nil {
+		return false
+	}
+
+	switch tv.Value.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(tv.Value)
+	case constant.String:
+		return constant.StringVal(tv.Value) == ""
+	case constant.Int, constant.Float:
+		return constant.Sign(tv.Value) == 0
+	}
+	return false
+}
+
+// fieldIndex returns the index of the field of typ with the given name,
+// excluding those of embedded structs, or -1 if there is no such field.
+func fieldIndex(typ *types.Struct, name string) int {
+	for i := 0; i < typ.NumFields(); i++ {
+		if typ.Field(i).Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// transpileListLit transpiles a slice or array literal into a list. length is
+// the length of arrays or -1 for slices. Elements may be keyed by their index
+// (e.g. `[]string{2: "c"}`) and any that aren't provided are zero values.
+func (t *Transpiler) transpileListLit(n *ast.CompositeLit, elem types.Type, length int64) Node {
+	var elts []Node
+
+	idx := int64(0)
+	for _, el := range n.Elts {
+		if kv, ok := el.(*ast.KeyValueExpr); ok {
+			idx, _ = constant.Int64Val(t.TypesInfo.Types[kv.Key].Value)
+			el = kv.Value
+		}
+
+		for int64(len(elts)) <= idx {
+			elts = append(elts, nil)
+		}
+
+		elts[idx] = t.convert(el, elem, t.transpileExpr(el))
+		idx++
+	}
+
+	for int64(len(elts)) < length {
+		elts = append(elts, nil)
+	}
+
+	for i, el := range elts {
+		if el == nil {
+			elts[i] = t.zeroOf(elem)
+		}
+	}
+
+	return &BuiltInCall{
+		FuncName:  "list",
+		Arguments: elts,
+	}
+}
+
+// transpileMapKey transpiles a key of a map. Maps are dicts in templates,
+// which only permit string keys, so integer keys are stringified as they
+// would be by encoding/json. Constant keys are transpiled into their string
+// value.
+func (t *Transpiler) transpileMapKey(key ast.Expr) Node {
+	if value := t.TypesInfo.Types[key].Value; value != nil {
+		switch value.Kind() {
+		case constant.String:
+			return NewLiteral(constant.StringVal(value))
+		case constant.Int:
+			return NewLiteral(value.ExactString())
+		}
+	}
+
+	if isInteger(t.typeOf(key)) {
+		return &BuiltInCall{FuncName: "toString", Arguments: []Node{t.transpileExpr(key)}}
+	}
+
+	return t.transpileExpr(key)
+}
+
+// isInteger returns true if typ's underlying type is an integer.
+func isInteger(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// maxLoopIterations is the maximum number of iterations of a for loop that
+// isn't a simple count (See transpileCountingLoop). Templates have no
+// equivalent of an unbounded loop so such loops are lowered into a range over
+// a list of this length. Exceeding it fails the template.
+const maxLoopIterations = 10000
+
+// transpileLoop transpiles the body of a loop, of any kind, and passes it to
+// lower to construct the loop itself.
+//
+// Templates are only able to break out of the innermost range, so return
+// statements within loops would otherwise continue executing the enclosing
+// function. If body contains a return statement, a flag is set upon
+// returning and checked after the loop, and any enclosing loops, to break
+// out of the function.
+func (t *Transpiler) transpileLoop(body *ast.BlockStmt, lower func(body Node) Node) Node {
+	if !containsReturn(body) {
+		return lower(t.transpileStatement(body))
+	}
+
+	var statements []Node
+
+	// The flag is declared by the outermost loop and shared with any inner
+	// loops.
+	if t.returned == nil {
+		t.returned = t.tmpVar("returned")
+		defer func() { t.returned = nil }()
+
+		statements = append(statements, &Assignment{LHS: t.returned, New: true, RHS: &Literal{Value: "false"}})
+	}
+
+	returned := t.returned
+
+	return &Block{Statements: append(statements,
+		lower(t.transpileStatement(body)),
+		&IfStmt{Cond: returned, Body: &Statement{NoCapture: true, Expr: &Literal{Value: "break"}}},
+	)}
+}
+
+// containsReturn returns true if body contains a return statement, excluding
+// those of function literals.
+func containsReturn(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// transpileCountingLoop transpiles for loops that simply count from a start
+// value to a stop value into a range over `untilStep`. It returns nil if stmt
+// is not such a loop.
+//
+//	for i := start; i < stop; i++ {}
+//	for i := start; i < stop; i += step {}
+//	for i := start; i > stop; i-- {}
+//	for i := start; i > stop; i -= step {}
+//
+// stop must be a variable, parameter, or field. Neither it, the counter, nor
+// step may be modified by the loop's body.
+func (t *Transpiler) transpileCountingLoop(stmt *ast.ForStmt, body Node) Node {
+	init, ok := stmt.Init.(*ast.AssignStmt)
+	if !ok || len(init.Lhs) != 1 || init.Tok != token.DEFINE {
+		return nil
+	}
+
+	counter, ok := init.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	cond, ok := stmt.Cond.(*ast.BinaryExpr)
+	if !ok || (cond.Op != token.LSS && cond.Op != token.GTR) {
+		return nil
+	}
+
+	// isCounter returns true if e is the loop's counter.
+	isCounter := func(e ast.Expr) bool {
+		ident, ok := e.(*ast.Ident)
+		return ok && t.TypesInfo.ObjectOf(ident) == t.TypesInfo.ObjectOf(counter)
+	}
+
+	if !isCounter(cond.X) {
+		return nil
+	}
+
+	if len(init.Rhs) != 1 {
+		return nil
+	}
+
+	start := t.transpileExpr(init.Rhs[0])
+	stop := t.transpileForBound(cond.Y)
+
+	var step Node
+	switch post := stmt.Post.(type) {
+	case *ast.AssignStmt:
+		if len(post.Lhs) != 1 || !isCounter(post.Lhs[0]) {
+			return nil
+		}
+
+		switch {
+		case cond.Op == token.LSS && post.Tok == token.ADD_ASSIGN:
+			step = t.transpileExpr(post.Rhs[0])
+		case cond.Op == token.GTR && post.Tok == token.SUB_ASSIGN:
+			step = &BuiltInCall{FuncName: "sub", Arguments: []Node{&Literal{Value: "0"}, t.transpileExpr(post.Rhs[0])}}
+		}
+
+		if t.assignsTo(stmt.Body, post.Rhs[0]) {
+			return nil
+		}
+
+	case *ast.IncDecStmt:
+		if !isCounter(post.X) {
+			return nil
+		}
+
+		switch {
+		case cond.Op == token.LSS && post.Tok == token.INC:
+			step = &Literal{Value: "1"}
+		case cond.Op == token.GTR && post.Tok == token.DEC:
+			step = &Literal{Value: "-1"}
+		}
+	}
+
+	if start == nil || stop == nil || step == nil || t.assignsTo(stmt.Body, cond.X) || t.assignsTo(stmt.Body, cond.Y) {
+		return nil
+	}
+
+	return &Range{
+		Key:   &Ident{Name: "_"},
+		Value: &Ident{Name: counter.Name},
+		Over: &UntilStep{
+			Start: start,
+			Stop:  stop,
+			Step:  step,
+		},
+		Body: body,
+	}
+}
+
+// transpileForBound transpiles the stop value of a counting loop's
+// condition. It returns nil if e isn't a variable, parameter, or field.
+func (t *Transpiler) transpileForBound(e ast.Expr) Node {
+	switch e.(type) {
+	case *ast.SelectorExpr, *ast.Ident:
+		return t.transpileExpr(e)
+	}
+	return nil
+}
+
+// assignsTo returns true if the variable referenced by e, if any, is assigned
+// to within body.
+func (t *Transpiler) assignsTo(body *ast.BlockStmt, e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	obj := t.TypesInfo.ObjectOf(ident)
+	if obj == nil {
+		return false
+	}
+
+	assigned := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		var lhs []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			lhs = n.Lhs
+		case *ast.IncDecStmt:
+			lhs = []ast.Expr{n.X}
+		}
+
+		for _, e := range lhs {
+			if ident, ok := e.(*ast.Ident); ok && t.TypesInfo.ObjectOf(ident) == obj {
+				assigned = true
+			}
+		}
+
+		return !assigned
+	})
+
+	return assigned
+}
+
+// transpileForLoop lowers any for loop into a range over a list of
+// maxLoopIterations+1 elements:
+//
+//	INIT
+//	range $iteration := until (maxLoopIterations+1)
+//		if $iteration > 0: POST
+//		if not COND: break
+//		if $iteration == maxLoopIterations: fail
+//		BODY
+//
+// Post statements are run at the start of every iteration, but the first,
+// rather than the end of each so that they are not skipped by continue
+// statements.
+func (t *Transpiler) transpileForLoop(stmt *ast.ForStmt, body Node) Node {
+	iteration := t.tmpVar("iteration")
+
+	var statements []Node
+
+	if stmt.Post != nil {
+		statements = append(statements, &IfStmt{
+			Cond: &BuiltInCall{FuncName: "gt", Arguments: []Node{iteration, &Literal{Value: "0"}}},
+			Body: t.transpileStatement(stmt.Post),
+		})
+	}
+
+	if stmt.Cond != nil {
+		statements = append(statements, &IfStmt{
+			Cond: &BuiltInCall{FuncName: "not", Arguments: []Node{t.transpileExpr(stmt.Cond)}},
+			Body: &Statement{NoCapture: true, Expr: &Literal{Value: "break"}},
+		})
+	}
+
+	pos := t.Fset.PositionFor(stmt.Pos(), true)
+	msg := fmt.Sprintf("for loop at %s:%d exceeded the maximum of %d iterations", filepath.Base(pos.Filename), pos.Line, maxLoopIterations)
+
+	statements = append(statements,
+		&IfStmt{
+			Cond: &BuiltInCall{FuncName: "eq", Arguments: []Node{iteration, &Literal{Value: strconv.Itoa(maxLoopIterations)}}},
+			Body: &Statement{Expr: &BuiltInCall{FuncName: "fail", Arguments: []Node{NewLiteral(msg)}}},
+		},
+		body,
+	)
+
+	loop := &Range{
+		Value: iteration,
+		Over:  &Until{Expr: &Literal{Value: strconv.Itoa(maxLoopIterations + 1)}},
+		Body:  &Block{Statements: statements},
+	}
+
+	if stmt.Init == nil {
+		return loop
+	}
+
+	return &Block{Statements: []Node{t.transpileStatement(stmt.Init), loop}}
+}
+
+// transpileSwitchStmt lowers expression switches, with or without a tag, into
+// an equivalent if-else chain. The tag, if any, is evaluated exactly once and
+// stored in a temporary variable.
+//
+//	switch x := f(); x {
+//	case 1, 2:
+//		fallthrough
+//	case 3:
+//	default:
+//	}
+//
+// Is transpiled as if it were written as:
+//
+//	x := f()
+//	tmp_switch_1 := x
+//	if tmp_switch_1 == 1 || tmp_switch_1 == 2 {
+//		// Body of case 1, 2 followed by the body of case 3.
+//	} else if tmp_switch_1 == 3 {
+//	} else {
+//	}
+func (t *Transpiler) transpileSwitchStmt(stmt *ast.SwitchStmt) Node {
+	var out []Node
+	if stmt.Init != nil {
+		out = append(out, t.transpileStatement(stmt.Init))
+	}
+
+	var tag Node
+	if stmt.Tag != nil {
+		tag = t.tmpVar("switch")
+		out = append(out, &Assignment{LHS: tag, New: true, RHS: t.transpileExpr(stmt.Tag)})
+	}
+
+	clauses := caseClauses(stmt.Body)
+	bodies := t.switchClauseBodies(clauses)
+
+	conds := make([]Node, len(clauses))
+	for i, clause := range clauses {
+		for _, expr := range clause.List {
+			var match Node
+			if tag == nil {
+				match = t.transpileExpr(expr)
+			} else {
+				match = t.transpileBinaryOp(expr, token.EQL, t.typeOf(stmt.Tag), t.typeOf(expr), tag, t.transpileExpr(expr))
+			}
+			conds[i] = orCond(conds[i], match)
+		}
+	}
+
+	if chain := switchToIfChain(conds, bodies); chain != nil {
+		out = append(out, chain)
+	}
+
+	return &Block{Statements: out}
+}
+
+// transpileTypeSwitchStmt lowers type switches into an equivalent if-else
+// chain of type tests. Numeric types are not supported for the same reasons
+// as in [helmette.TypeTest].
+//
+//	switch x := v.(type) {
+//	case string, bool:
+//	case map[string]any:
+//	case nil:
+//	}
+//
+// Is transpiled as if it were written as:
+//
+//	tmp_typeswitch_1 := v
+//	if typetest(string, tmp_typeswitch_1) || typetest(bool, tmp_typeswitch_1) {
+//		x := tmp_typeswitch_1
+//	} else if typetest(map[string]any, tmp_typeswitch_1) {
+//		x := tmp_typeswitch_1
+//	} else if tmp_typeswitch_1 == nil {
+//		x := tmp_typeswitch_1
+//	}
+func (t *Transpiler) transpileTypeSwitchStmt(stmt *ast.TypeSwitchStmt) Node {
+	var out []Node
+	if stmt.Init != nil {
+		out = append(out, t.transpileStatement(stmt.Init))
+	}
+
+	var assert *ast.TypeAssertExpr
+	switch s := stmt.Assign.(type) {
+	case *ast.AssignStmt:
+		assert = s.Rhs[0].(*ast.TypeAssertExpr)
+	case *ast.ExprStmt:
+		assert = s.X.(*ast.TypeAssertExpr)
+	}
+
+	t.checkDispatchedAssertion(assert.X)
+
+	value := t.tmpVar("typeswitch")
+	out = append(out, &Assignment{LHS: value, New: true, RHS: t.transpileExpr(assert.X)})
+
+	clauses := caseClauses(stmt.Body)
+	bodies := t.switchClauseBodies(clauses)
+
+	conds := make([]Node, len(clauses))
+	for i, clause := range clauses {
+		for _, expr := range clause.List {
+			var match Node
+			typ := t.typeOf(expr)
+
+			if t.TypesInfo.Types[expr].IsNil() {
+				match = &BuiltInCall{FuncName: "eq", Arguments: []Node{value, &Nil{}}}
+			} else if iface, ok := typ.Underlying().(*types.Interface); ok {
+				// All non-nil values satisfy the empty interface. Any other
+				// interface would require knowledge of method sets which
+				// don't exist in templates.
+				if !iface.Empty() {
+					panic(&Unsupported{
+						Node: expr,
+						Fset: t.Fset,
+						Msg:  "type switch cases on non-empty interfaces are not supported",
+					})
+				}
+				match = &BuiltInCall{FuncName: "ne", Arguments: []Node{value, &Nil{}}}
+			} else {
+				match = &BuiltInCall{
+					FuncName:  "index",
+					Arguments: []Node{t.transpileTypeTest(expr, typ, value), &Literal{Value: "1"}},
+				}
+			}
+
+			conds[i] = orCond(conds[i], match)
+		}
+
+		// If the switch binds a variable (x := v.(type)), each clause
+		// implicitly declares its own version of it. As templates are
+		// untyped, the value of the switch may be used as is.
+		if obj, ok := t.TypesInfo.Implicits[clause]; ok {
+			bodies[i].Statements = append([]Node{
+				&Assignment{LHS: &Ident{Name: obj.Name()}, New: true, RHS: value},
+			}, bodies[i].Statements...)
+		}
+	}
+
+	if chain := switchToIfChain(conds, bodies); chain != nil {
+		out = append(out, chain)
+	}
+
+	return &Block{Statements: out}
+}
+
+func caseClauses(body *ast.BlockStmt) []*ast.CaseClause {
+	var clauses []*ast.CaseClause
+	for _, s := range body.List {
+		clauses = append(clauses, s.(*ast.CaseClause))
+	}
+	return clauses
+}
+
+// orCond joins two conditions with `or`. cond may be nil.
+func orCond(cond, match Node) Node {
+	if cond == nil {
+		return match
+	}
+	return &BuiltInCall{FuncName: "or", Arguments: []Node{cond, match}}
+}
+
+// switchToIfChain returns an if-else chain of the provided conditions and
+// bodies. A nil condition indicates the default case, which will always be
+// the final else regardless of its position. It returns nil if there are no
+// cases.
+func switchToIfChain(conds []Node, bodies []*Block) Node {
+	var chain Node
+	for i := range conds {
+		if conds[i] == nil {
+			chain = bodies[i]
+		}
+	}
+
+	// Build the if-else chain from the bottom up so the default case, if
+	// any, ends up as the final else.
+	for i := len(conds) - 1; i >= 0; i-- {
+		if conds[i] != nil {
+			chain = &IfStmt{Cond: conds[i], Body: bodies[i], Else: chain}
+		}
+	}
+
+	return chain
+}
+
+// switchClauseBodies returns the transpiled bodies of the provided case
+// clauses. Trailing `fallthrough` statements are handled by appending the body
+// of the following clause. Trailing `break` statements are a noop and are
+// dropped. Any other `break` that would target the switch is unsupported as
+// it would otherwise break out of the enclosing range.
+func (t *Transpiler) switchClauseBodies(clauses []*ast.CaseClause) []*Block {
+	stmts := make([][]ast.Stmt, len(clauses))
+	for i := len(clauses) - 1; i >= 0; i-- {
+		body := clauses[i].Body
+
+		if n := len(body); n > 0 {
+			if branch, ok := body[n-1].(*ast.BranchStmt); ok && branch.Label == nil {
+				switch branch.Tok {
+				case token.FALLTHROUGH:
+					body = append(body[:n-1:n-1], stmts[i+1]...)
+				case token.BREAK:
+					body = body[:n-1]
+				}
+			}
+		}
+
+		stmts[i] = body
+	}
+
+	bodies := make([]*Block, len(clauses))
+	for i, body := range stmts {
+		var out []Node
+		for _, s := range body {
+			t.checkSwitchBreaks(s)
+			out = append(out, t.transpileStatement(s))
+		}
+		bodies[i] = &Block{Statements: out}
+	}
+
+	return bodies
+}
+
+// checkSwitchBreaks panics with an [Unsupported] if the provided statement
+// contains a `break` that targets an enclosing switch statement.
+func (t *Transpiler) checkSwitchBreaks(stmt ast.Stmt) {
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+			// Any break statements within these nodes will target them
+			// rather than the switch.
+			return false
+
+		case *ast.BranchStmt:
+			if n.Tok == token.BREAK && n.Label == nil {
+				panic(&Unsupported{
+					Node: n,
+					Fset: t.Fset,
+					Msg:  "break statements within switch cases are only supported as the final statement of a case",
+				})
+			}
+		}
+		return true
+	})
+}
+
+func (t *Transpiler) transpileExpr(n ast.Expr) (_ Node) {
+	defer t.recoverUnsupported(n)
+
+	switch n := n.(type) {
+	case nil:
+		return nil
+
+	case *ast.BasicLit:
+		return t.maybeCast(&Literal{Value: n.Value}, t.typeOf(n))
+
+	case *ast.ParenExpr:
+		return &ParenExpr{Expr: t.transpileExpr(n.X)}
+
+	case *ast.FuncLit:
+		return t.transpileFuncLit(n)
+
+	case *ast.StarExpr:
+		// TODO this should be wrapped in something like "Assert not nil"
+		return t.transpileExpr(n.X)
+
+	case *ast.SliceExpr:
+		target := t.transpileExpr(n.X)
+		low := t.transpileExpr(n.Low)
+		high := t.transpileExpr(n.High)
+		max := t.transpileExpr(n.Max)
+
+		// If low isn't specified it defaults to zero
+		if low == nil {
+			low = &Literal{Value: "0"}
+		}
+
+		// The builtin `slice` function from go would work great here but sprig
+		// overwrites it for some reason with a worse version.
 		if t.isString(n.X) {
 			// NB: Triple slicing a string (""[1:2:3]) isn't valid. No need to
 			// check .Max or .Slice3.
@@ -516,25 +2010,26 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 
 		switch underlying := typ.Underlying().(type) {
 		case *types.Slice:
-			var elts []Node
-			for _, el := range n.Elts {
-				elts = append(elts, t.transpileExpr(el))
-			}
-			return &BuiltInCall{
-				FuncName:  "list",
-				Arguments: elts,
-			}
+			return t.transpileListLit(n, underlying.Elem(), -1)
+
+		case *types.Array:
+			return t.transpileListLit(n, underlying.Elem(), underlying.Len())
 
 		case *types.Map:
-			if !types.AssignableTo(underlying.Key(), types.Typ[types.String]) {
-				panic(fmt.Sprintf("map keys must be string. Got %#v", underlying.Key()))
+			if basic, ok := underlying.Key().Underlying().(*types.Basic); !ok || basic.Info()&(types.IsString|types.IsInteger) == 0 {
+				panic(&Unsupported{
+					Node: n,
+					Fset: t.Fset,
+					Msg:  fmt.Sprintf("map keys must be strings or integers. Got %v", underlying.Key()),
+				})
 			}
 
 			var d DictLiteral
 			for _, el := range n.Elts {
+				value := el.(*ast.KeyValueExpr).Value
 				d.KeysValues = append(d.KeysValues, &KeyValue{
-					Key:   el.(*ast.KeyValueExpr).Key.(*ast.BasicLit).Value,
-					Value: t.transpileExpr(el.(*ast.KeyValueExpr).Value),
+					Key:   t.transpileMapKey(el.(*ast.KeyValueExpr).Key),
+					Value: t.convert(value, underlying.Elem(), t.transpileExpr(value)),
 				})
 			}
 			return &d
@@ -542,31 +2037,44 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 		case *types.Struct:
 			zero := t.zeroOf(typ)
 			fields := t.getFields(underlying)
-			fieldByName := map[string]*structField{}
+			fieldByVar := map[*types.Var]*structField{}
 			for _, f := range fields {
 				f := f
-				fieldByName[f.Field.Name()] = &f
+				fieldByVar[f.Field] = &f
 			}
 
 			var embedded []Node
 			var d DictLiteral
-			for _, el := range n.Elts {
-				key := el.(*ast.KeyValueExpr).Key.(*ast.Ident).Name
-				value := el.(*ast.KeyValueExpr).Value
+			for i, el := range n.Elts {
+				// Elements are either keyed by field name or, if positional,
+				// provide every field in order of declaration.
+				value := el
+				kv, keyed := el.(*ast.KeyValueExpr)
+				if keyed {
+					i = fieldIndex(underlying, kv.Key.(*ast.Ident).Name)
+					value = kv.Value
+				}
+
+				field := fieldByVar[underlying.Field(i)]
 
-				field := fieldByName[key]
 				if field.JSONOmit() {
 					continue
 				}
 
+				// Positional literals provide every field, including zero
+				// values of omitempty fields that would otherwise be included.
+				if !keyed && !field.IncludeInZero() && t.isZeroConst(value) {
+					continue
+				}
+
 				if field.JSONInline() {
 					embedded = append(embedded, t.transpileExpr(value))
 					continue
 				}
 
 				d.KeysValues = append(d.KeysValues, &KeyValue{
-					Key:   strconv.Quote(field.JSONName()),
-					Value: t.transpileExpr(value),
+					Key:   NewLiteral(field.JSONName()),
+					Value: t.convert(value, underlying.Field(i).Type(), t.transpileExpr(value)),
 				})
 			}
 
@@ -580,7 +2088,11 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 			}
 
 		default:
-			panic(fmt.Sprintf("unsupported composite literal %#v", typ))
+			panic(&Unsupported{
+				Node: n,
+				Fset: t.Fset,
+				Msg:  fmt.Sprintf("unsupported composite literal of type %v", typ),
+			})
 		}
 
 	case *ast.CallExpr:
@@ -595,8 +2107,14 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 			return &Nil{}
 
 		case *types.Var:
+			if isPackageVar(obj) {
+				return t.transpilePackageVar(n, obj)
+			}
 			return &Ident{Name: obj.Name()}
 
+		case *types.Func:
+			return t.transpileFuncValue(n, obj)
+
 		// Unclear how often this check is correct. true, false, and _ won't
 		// have an Obj. AST rewriting can also result in .Obj being nil.
 		case nil:
@@ -619,6 +2137,12 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 			return t.transpileConst(obj)
 
 		case *types.Func:
+			// References to functions of helper packages (helpers.Fn), rather
+			// than methods, are function values.
+			if obj.Type().(*types.Signature).Recv() == nil {
+				return t.transpileFuncValue(n, obj)
+			}
+
 			// TODO this needs better documentation
 			// And probably needs a more aggressive check.
 			return &Selector{
@@ -627,6 +2151,11 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 			}
 
 		case *types.Var:
+			// References to variables of helper packages (helpers.Var).
+			if isPackageVar(obj) {
+				return t.transpilePackageVar(n, obj)
+			}
+
 			// If our selector is a variable, we're probably accessing a field
 			// on a struct.
 			typ := t.typeOf(n.X)
@@ -653,187 +2182,535 @@ func (t *Transpiler) transpileExpr(n ast.Expr) Node {
 		})
 
 	case *ast.BinaryExpr:
-		untyped := [3]string{"_", n.Op.String(), "_"}
-		typed := [3]string{t.typeOf(n.X).String(), n.Op.String(), t.typeOf(n.Y).String()}
+		// Concatenations of string constants are evaluated at compile time.
+		if tv := t.TypesInfo.Types[n]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			return &Literal{Value: tv.Value.ExactString()}
+		}
+
+		x, y := t.transpileExpr(n.X), t.transpileExpr(n.Y)
+
+		// Operands of comparisons are converted to the type of the other,
+		// if it's an interface.
+		if n.Op == token.EQL || n.Op == token.NEQ {
+			x, y = t.convert(n.X, t.typeOf(n.Y), x), t.convert(n.Y, t.typeOf(n.X), y)
+		}
+
+		return t.transpileBinaryOp(n, n.Op, t.typeOf(n.X), t.typeOf(n.Y), x, y)
+
+	case *ast.UnaryExpr:
+		switch n.Op {
+		case token.NOT:
+			return &BuiltInCall{
+				FuncName:  "not",
+				Arguments: []Node{t.transpileExpr(n.X)},
+			}
+		case token.AND:
+			// Can't take addresses in templates so just return the variable.
+			return t.transpileExpr(n.X)
+		case token.SUB:
+			if i, ok := n.X.(*ast.BasicLit); ok {
+				return &Literal{Value: fmt.Sprintf("-%s", i.Value)}
+			}
+		}
+
+	case *ast.IndexExpr:
+		return &BuiltInCall{
+			FuncName: "index",
+			Arguments: []Node{
+				t.transpileExpr(n.X),
+				t.transpileIndex(n.X, n.Index),
+			},
+		}
+
+	case *ast.TypeAssertExpr:
+		t.checkDispatchedAssertion(n.X)
+
+		typ := t.typeOf(n.Type)
+
+		if basic, ok := typ.(*types.Basic); ok && (basic.Info()&types.IsNumeric != 0) {
+			panic(&Unsupported{
+				Node:        n,
+				Fset:        t.Fset,
+				Msg:         "type assertions on numeric types are unreliable due to JSON casting all numbers to float64's",
+				Alternative: "helmette.AsNumeric or helmette.AsIntegral",
+			})
+		}
+
+		return &Call{
+			FuncName: "_shims.typeassertion",
+			Arguments: []Node{
+				t.transpileTypeRepr(typ),
+				t.transpileExpr(n.X),
+			},
+		}
+	}
+
+	panic(&Unsupported{
+		Node: n,
+		Fset: t.Fset,
+		Msg:  "unhandled ast.Expr",
+	})
+}
+
+// mkPkgTree "flattens" a loaded [packages.Package] and its dependencies into a
+// map keyed by path.
+func mkPkgTree(root *packages.Package) map[string]*packages.Package {
+	tree := map[string]*packages.Package{}
+	toVisit := []*packages.Package{root}
+
+	// The naive approach here is crazy slow so instead we do a memomized
+	// implementation.
+	var pkg *packages.Package
+	for len(toVisit) > 0 {
+		pkg, toVisit = toVisit[0], toVisit[1:]
+
+		if _, ok := tree[pkg.PkgPath]; ok {
+			continue
+		}
+
+		tree[pkg.PkgPath] = pkg
+
+		for _, imported := range pkg.Imports {
+			toVisit = append(toVisit, imported)
+		}
+	}
+
+	return tree
+}
+
+// transpileFuncLit lowers a function literal into a synthesized [Func],
+// named after its enclosing function (e.g. Outer.func1) or package level
+// variable, and returns a [Closure] of it.
+//
+// Variables of enclosing functions that are referenced by the literal are
+// captured when the literal is evaluated and passed to the synthesized [Func]
+// ahead of its parameters. Templates have no equivalent of go's capture by
+// reference, so the semantics differ slightly:
+//   - Maps and structs are dicts, which are references in templates.
+//     Mutations of their contents (m[k] = v, s.F = v) are visible to both
+//     the closure and its enclosing function, as they would be in go.
+//   - Captured variables are otherwise copies. Reassignments made by the
+//     enclosing function after the closure has been created are not visible
+//     to the closure. Closures may not assign to captured variables.
+func (t *Transpiler) transpileFuncLit(lit *ast.FuncLit) Node {
+	var enclosing string
+	if decl := findNearest[*ast.FuncDecl](t.Package, lit.Pos()); decl != nil {
+		enclosing = t.declNameFor(decl)
+	} else {
+		enclosing = t.varNameFor(lit)
+	}
+
+	// Reserve a slot before transpiling the body so that nested literals
+	// are numbered in order of appearance.
+	idx := len(t.closures)
+	t.closures = append(t.closures, nil)
+
+	var params, captures []Node
+	for _, v := range t.capturesOf(lit) {
+		params = append(params, &Ident{Name: v.Name()})
+		captures = append(captures, &Ident{Name: v.Name()})
+	}
+
+	for _, field := range lit.Type.Params.List {
+		// Unnamed parameters still occupy a position.
+		if len(field.Names) == 0 {
+			params = append(params, &Ident{Name: "_"})
+		}
+		for _, name := range field.Names {
+			params = append(params, t.transpileExpr(name))
+		}
+	}
+
+	// Loops within the literal are unrelated to any enclosing the literal.
+	returned, results := t.returned, t.results
+	t.returned = nil
+	t.results = t.TypesInfo.TypeOf(lit).(*types.Signature).Results()
+
+	var statements []Node
+	for _, stmt := range lit.Body.List {
+		statements = append(statements, t.transpileStatement(stmt))
+	}
+
+	t.returned, t.results = returned, results
+
+	fn := &Func{
+		Name:       fmt.Sprintf("%s.func%d", enclosing, idx+1),
+		Namespace:  t.namespaceFor(t.Package.Types),
+		Source:     t.Fset.PositionFor(lit.Pos(), true),
+		Params:     params,
+		Statements: statements,
+	}
+
+	t.closures[idx] = fn
+
+	return &Closure{FuncName: fn.Namespace + "." + fn.Name, Captures: captures}
+}
+
+// varNameFor returns the name of the package level variable whose initial
+// value contains lit.
+func (t *Transpiler) varNameFor(lit *ast.FuncLit) string {
+	spec := findNearest[*ast.ValueSpec](t.Package, lit.Pos())
+	for i, value := range spec.Values {
+		if value.Pos() <= lit.Pos() && lit.End() <= value.End() {
+			return spec.Names[i].Name
+		}
+	}
+	panic(errors.Newf("%s is not within a package level variable", t.Fset.Position(lit.Pos())))
+}
+
+// capturesOf returns the variables of enclosing functions that are
+// referenced by lit, in order of first reference. It panics with an
+// [Unsupported] if lit assigns to any of them.
+func (t *Transpiler) capturesOf(lit *ast.FuncLit) []*types.Var {
+	scope := t.TypesInfo.Scopes[lit.Type]
+
+	isCapture := func(ident *ast.Ident) (*types.Var, bool) {
+		v, ok := t.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || v.IsField() || v.Pkg() == nil || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+			return nil, false
+		}
+
+		for s := v.Parent(); s != nil; s = s.Parent() {
+			if s == scope {
+				return nil, false
+			}
+		}
+
+		return v, true
+	}
+
+	seen := map[*types.Var]bool{}
+	var captures []*types.Var
+
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if v, ok := isCapture(ident); ok && !seen[v] {
+				seen[v] = true
+				captures = append(captures, v)
+			}
+		}
+
+		for _, ident := range t.reassignedBy(n) {
+			if _, ok := isCapture(ident); ok {
+				panic(&Unsupported{
+					Node: n,
+					Fset: t.Fset,
+					Msg:  fmt.Sprintf("function literals may not assign to captured variables (%s)", ident.Name),
+				})
+			}
+		}
+
+		return true
+	})
+
+	// Captures are copied into the closure, so it would not observe
+	// reassignments made after its creation.
+	for _, v := range captures {
+		if n := t.reassignmentAfter(lit, v); n != nil {
+			panic(&Unsupported{
+				Node: n,
+				Fset: t.Fset,
+				Msg:  fmt.Sprintf("variables captured by function literals may not be reassigned after the literal (%s)", v.Name()),
+			})
+		}
+	}
+
+	return captures
+}
+
+// reassignedBy returns the identifiers of the variables that are reassigned
+// by n, if n is a statement. Modifications of slices, be it of their elements
+// or in place (e.g. `slices.Sort(x)`), reassign the variable holding them. See
+// [Transpiler.assignBack].
+func (t *Transpiler) reassignedBy(n ast.Node) []*ast.Ident {
+	var assigned []ast.Expr
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		assigned = n.Lhs
+	case *ast.IncDecStmt:
+		assigned = []ast.Expr{n.X}
+	case *ast.RangeStmt:
+		if n.Tok == token.ASSIGN {
+			assigned = []ast.Expr{n.Key, n.Value}
+		}
+	case *ast.ExprStmt:
+		if call, ok := n.X.(*ast.CallExpr); ok && t.modifiesInPlace(call) {
+			assigned = call.Args[:1]
+		}
+	}
+
+	var idents []*ast.Ident
+	for _, e := range assigned {
+		for e != nil {
+			switch x := ast.Unparen(e).(type) {
+			case *ast.Ident:
+				// Identifiers declared by := are recorded in Defs,
+				// redeclared ones in Uses.
+				if _, ok := t.TypesInfo.Uses[x]; ok {
+					idents = append(idents, x)
+				}
+				e = nil
+			case *ast.IndexExpr:
+				if t.isMap(x.X) {
+					e = nil
+				} else {
+					e = x.X
+				}
+			default:
+				e = nil
+			}
+		}
+	}
+	return idents
+}
 
-		f := func(op string) func(a, b Node) Node {
-			return func(a, b Node) Node {
-				return &BuiltInCall{FuncName: op, Arguments: []Node{a, b}}
+// reassignmentAfter returns the first statement outside of lit that
+// reassigns v after lit is evaluated, if any. That is, any following lit or,
+// if lit is within a loop that v is declared outside of, any within said
+// loop.
+func (t *Transpiler) reassignmentAfter(lit *ast.FuncLit, v *types.Var) ast.Node {
+	file := findNearest[*ast.File](t.Package, lit.Pos())
+
+	after := lit.End()
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil || n.Pos() > lit.Pos() || n.End() < lit.End() {
+			return false
+		}
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			if n.Pos() > v.Pos() && n.Pos() < after {
+				after = n.Pos()
 			}
 		}
+		return true
+	})
 
-		wrapWithCast := func(op, cast string) func(a, b Node) Node {
-			return func(a, b Node) Node {
-				return &Cast{To: cast, X: &BuiltInCall{FuncName: op, Arguments: []Node{a, b}}}
+	var found ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found != nil || n == nil || n == lit || n.End() < after {
+			return false
+		}
+		if n.Pos() >= after {
+			for _, ident := range t.reassignedBy(n) {
+				if t.TypesInfo.Uses[ident] == v {
+					found = n
+				}
 			}
 		}
+		return found == nil
+	})
+	return found
+}
 
-		// Poor man's pattern matching :[
-		mapping := map[[3]string]func(a, b Node) Node{
-			{"_", token.EQL.String(), "_"}:  f("eq"),
-			{"_", token.NEQ.String(), "_"}:  f("ne"),
-			{"_", token.LAND.String(), "_"}: f("and"),
-			{"_", token.LOR.String(), "_"}:  f("or"),
-			{"_", token.GTR.String(), "_"}:  f("gt"),
-			{"_", token.LSS.String(), "_"}:  f("lt"),
-			{"_", token.GEQ.String(), "_"}:  f("ge"),
-			{"_", token.LEQ.String(), "_"}:  f("le"),
+// transpileFuncValue returns a [Closure] of the function fn, which is
+// referenced but not called by n. e.g. `sort(items, less)`.
+func (t *Transpiler) transpileFuncValue(n ast.Expr, fn *types.Func) Node {
+	if fn.Pkg() == nil || (fn.Pkg().Path() != t.Package.PkgPath && !isHelperPackage(fn.Pkg())) {
+		panic(&Unsupported{
+			Node: n,
+			Fset: t.Fset,
+			Msg:  fmt.Sprintf("only functions that are transpiled may be used as values. got: %s", fn.FullName()),
+		})
+	}
+
+	return &Closure{FuncName: fmt.Sprintf("%s.%s", t.namespaceFor(fn.Pkg()), t.calleeNameFor(fn, n))}
+}
 
-			{"float32", token.ADD.String(), "float32"}: wrapWithCast("addf", "float64"),
-			{"float32", token.MUL.String(), "float32"}: wrapWithCast("mulf", "float64"),
-			{"float32", token.QUO.String(), "float32"}: wrapWithCast("divf", "float32"),
-			{"float32", token.SUB.String(), "float32"}: wrapWithCast("subf", "float64"),
+// transpileConversion transpiles the conversion of x, which transpiled to
+// arg, to the named type typ. Named types share the representation of their
+// underlying type so only conversions to numbers or from numbers to strings
+// have any effect.
+func (t *Transpiler) transpileConversion(x ast.Expr, arg Node, typ *types.Named) Node {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return arg
+	}
 
-			{"float64", token.ADD.String(), "float64"}: wrapWithCast("addf", "float64"),
-			{"float64", token.MUL.String(), "float64"}: wrapWithCast("mulf", "float64"),
-			{"float64", token.QUO.String(), "float64"}: wrapWithCast("divf", "float64"),
-			{"float64", token.SUB.String(), "float64"}: wrapWithCast("subf", "float64"),
+	if basic.Info()&types.IsString != 0 && isInteger(t.typeOf(x)) {
+		return &BuiltInCall{FuncName: "printf", Arguments: []Node{&Literal{Value: `"%c"`}, arg}}
+	}
 
-			{"int", token.ADD.String(), "int"}: wrapWithCast("add", "int"),
-			{"int", token.MUL.String(), "int"}: wrapWithCast("mul", "int"),
-			{"int", token.QUO.String(), "int"}: wrapWithCast("div", "int"),
-			{"int", token.REM.String(), "int"}: wrapWithCast("mod", "int"),
-			{"int", token.SUB.String(), "int"}: wrapWithCast("sub", "int"),
+	return t.maybeCast(arg, basic)
+}
 
-			{"int32", token.ADD.String(), "int32"}: wrapWithCast("add", "int"),
-			{"int32", token.MUL.String(), "int32"}: wrapWithCast("mul", "int"),
-			{"int32", token.QUO.String(), "int32"}: wrapWithCast("div", "int"),
-			{"int32", token.REM.String(), "int32"}: wrapWithCast("mod", "int"),
-			{"int32", token.SUB.String(), "int32"}: wrapWithCast("sub", "int"),
+// base64Encoding returns the name of x, the receiver of a method of
+// [base64.Encoding], if it's one of the encodings declared by encoding/base64
+// (e.g. "StdEncoding"). Otherwise it returns "".
+func base64Encoding(info *types.Info, x ast.Expr) string {
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
 
-			{"int64", token.ADD.String(), "int64"}: wrapWithCast("add", "int64"),
-			{"int64", token.MUL.String(), "int64"}: wrapWithCast("mul", "int64"),
-			{"int64", token.QUO.String(), "int64"}: wrapWithCast("div", "int64"),
-			{"int64", token.REM.String(), "int64"}: wrapWithCast("mod", "int64"),
-			{"int64", token.SUB.String(), "int64"}: wrapWithCast("sub", "int64"),
+	obj := info.ObjectOf(sel.Sel)
+	if !isPackageVar(obj) || obj.Pkg().Path() != "encoding/base64" {
+		return ""
+	}
+	return obj.Name()
+}
 
-			{"untyped int", token.ADD.String(), "untyped int"}: f("add"),
-			{"untyped int", token.MUL.String(), "untyped int"}: f("mul"),
-			{"untyped int", token.QUO.String(), "untyped int"}: f("div"),
-			{"untyped int", token.REM.String(), "untyped int"}: f("mod"),
-			{"untyped int", token.SUB.String(), "untyped int"}: f("sub"),
+// errorDiscarded returns true if the error returned by call is discarded.
+// Tuple assignments are rewritten into [helmette.Compact2] (See
+// `rewriteMultiValueReturns`), so the error is discarded if the `T2` field
+// of the tuple that call is compacted into is never referenced.
+func (t *Transpiler) errorDiscarded(call *ast.CallExpr) bool {
+	// NB: Rewritten nodes may lack positions, so the tuple is found by
+	// identity rather than with findNearest.
+	var tuple *ast.Ident
+	for _, f := range t.Package.Syntax {
+		ast.Inspect(f, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+				return tuple == nil
+			}
+			compact, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+			ident, isIdent := assign.Lhs[0].(*ast.Ident)
+			if ok && isIdent && len(compact.Args) == 1 && ast.Unparen(compact.Args[0]) == call {
+				tuple = ident
+			}
+			return tuple == nil
+		})
+	}
 
-			{"untyped float", token.ADD.String(), "untyped float"}: f("addf"),
-			{"untyped float", token.MUL.String(), "untyped float"}: f("mulf"),
-			{"untyped float", token.QUO.String(), "untyped float"}: f("divf"),
-			{"untyped float", token.SUB.String(), "untyped float"}: f("subf"),
+	discarded := tuple != nil
+	if tuple != nil && tuple.Name != "_" {
+		obj := t.TypesInfo.ObjectOf(tuple)
+		for _, f := range t.Package.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "T2" {
+					if ident, ok := sel.X.(*ast.Ident); ok && t.TypesInfo.Uses[ident] == obj {
+						discarded = false
+					}
+				}
+				return discarded
+			})
 		}
+	}
 
-		// Typed versions take precedence.
-		if funcName, ok := mapping[typed]; ok {
-			return funcName(t.transpileExpr(n.X), t.transpileExpr(n.Y))
-		}
+	return discarded
+}
 
-		// Fallback to "wild cards" (_).
-		if funcName, ok := mapping[untyped]; ok {
-			return funcName(t.transpileExpr(n.X), t.transpileExpr(n.Y))
-		}
+// isByteSlice returns true if typ is a slice of bytes. e.g. []byte.
+func isByteSlice(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	return ok && types.Identical(slice.Elem(), types.Typ[types.Byte])
+}
 
+// transpilePackageVar transpiles a reference to a package level variable
+// into a call of the function that returns its value. See transpileVarSpec.
+func (t *Transpiler) transpilePackageVar(n ast.Expr, v *types.Var) Node {
+	if v.Pkg().Path() != t.Package.PkgPath && !isHelperPackage(v.Pkg()) {
 		panic(&Unsupported{
 			Node: n,
 			Fset: t.Fset,
-			Msg:  fmt.Sprintf(`No matching %T signature for %v or %v`, n, typed, untyped),
+			Msg:  fmt.Sprintf("only package level variables of transpiled packages may be referenced. got: %s.%s", v.Pkg().Path(), v.Name()),
 		})
+	}
 
-		// TODO re-add suport for rewriting str + str into printf "%s%s". For
-		// now its easier to just require writers to use printf
-		// No support for easy string concatenation in helm/sprig/templates soooo. Printf.
-		// if t.isString(n.Y) && t.isString(n.X) {
-		// 	return &BuiltInCall{
-		// 		FuncName: "printf",
-		// 		Arguments: []Node{
-		// 			&Literal{Value: `"%s%s"`},
-		// 			t.transpileExpr(n.X),
-		// 			t.transpileExpr(n.Y),
-		// 		},
-		// 	}
-		// }
+	return t.maybeCast(&Call{FuncName: fmt.Sprintf("%s.%s", t.namespaceFor(v.Pkg()), v.Name())}, v.Type())
+}
 
-	case *ast.UnaryExpr:
-		switch n.Op {
-		case token.NOT:
-			return &BuiltInCall{
-				FuncName:  "not",
-				Arguments: []Node{t.transpileExpr(n.X)},
+// isPackageVar returns true if obj is a package level variable.
+func isPackageVar(obj types.Object) bool {
+	v, ok := obj.(*types.Var)
+	return ok && !v.IsField() && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
+
+// checkAssignable panics with an [Unsupported] if e, the left hand side of an
+// assignment, is or is within a package level variable.
+func (t *Transpiler) checkAssignable(e ast.Expr) {
+	for {
+		switch x := e.(type) {
+		case *ast.ParenExpr:
+			e = x.X
+			continue
+		case *ast.SelectorExpr:
+			if isPackageVar(t.TypesInfo.ObjectOf(x.Sel)) {
+				break
 			}
-		case token.AND:
-			// Can't take addresses in templates so just return the variable.
-			return t.transpileExpr(n.X)
-		case token.SUB:
-			if i, ok := n.X.(*ast.BasicLit); ok {
-				return &Literal{Value: fmt.Sprintf("-%s", i.Value)}
+			e = x.X
+			continue
+		case *ast.IndexExpr:
+			e = x.X
+			continue
+		case *ast.StarExpr:
+			e = x.X
+			continue
+		case *ast.Ident:
+			if !isPackageVar(t.TypesInfo.ObjectOf(x)) {
+				return
 			}
+		default:
+			return
 		}
 
-	case *ast.IndexExpr:
-		return &BuiltInCall{
-			FuncName: "index",
-			Arguments: []Node{
-				t.transpileExpr(n.X),
-				t.transpileExpr(n.Index),
-			},
-		}
-
-	case *ast.TypeAssertExpr:
-		typ := t.typeOf(n.Type)
-
-		if basic, ok := typ.(*types.Basic); ok && (basic.Info()&types.IsNumeric != 0) {
-			panic(&Unsupported{
-				Node: n,
-				Fset: t.Fset,
-				Msg:  "type assertions on numeric types are unreliable due to JSON casting all numbers to float64's. Instead use `helmette.IsNumeric` or `helmette.AsIntegral`",
-			})
-		}
+		panic(&Unsupported{
+			Node: e,
+			Fset: t.Fset,
+			Msg:  "package level variables may not be assigned to",
+		})
+	}
+}
 
-		return &Call{
-			FuncName: "_shims.typeassertion",
-			Arguments: []Node{
-				t.transpileTypeRepr(typ),
-				t.transpileExpr(n.X),
-			},
-		}
+// isFuncValue returns true if fun, the function of a call expression, is a
+// function value (e.g. a variable holding a closure) rather than a named
+// function, builtin, or type conversion.
+func (t *Transpiler) isFuncValue(fun ast.Expr, callee types.Object) bool {
+	if _, ok := callee.(*types.Var); ok {
+		return true
 	}
 
-	var b bytes.Buffer
-	if err := format.Node(&b, t.Fset, n); err != nil {
-		panic(err)
+	if callee != nil {
+		return false
 	}
-	panic(fmt.Sprintf("unhandled Expr %T\n%s", n, b.String()))
+
+	tv := t.TypesInfo.Types[fun]
+	return !tv.IsType() && !tv.IsBuiltin()
 }
 
-// mkPkgTree "flattens" a loaded [packages.Package] and its dependencies into a
-// map keyed by path.
-func mkPkgTree(root *packages.Package) map[string]*packages.Package {
-	tree := map[string]*packages.Package{}
-	toVisit := []*packages.Package{root}
+func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
+	var args []Node
+	for _, arg := range n.Args {
+		args = append(args, t.transpileExpr(arg))
+	}
 
-	// The naive approach here is crazy slow so instead we do a memomized
-	// implementation.
-	var pkg *packages.Package
-	for len(toVisit) > 0 {
-		pkg, toVisit = toVisit[0], toVisit[1:]
+	t.convertArgs(n, args)
 
-		if _, ok := tree[pkg.PkgPath]; ok {
-			continue
+	callee := typeutil.Callee(t.TypesInfo, n)
+
+	// Calls of function values, such as closures.
+	if t.isFuncValue(n.Fun, callee) {
+		signature := t.typeOf(n.Fun).Underlying().(*types.Signature)
+
+		// Arguments passed by value are copies. The callee isn't known so
+		// the copies can't be elided.
+		for i := 0; i < signature.Params().Len() && i < len(n.Args); i++ {
+			if signature.Variadic() && i == signature.Params().Len()-1 {
+				break
+			}
+			args[i] = t.copyValue(n.Args[i], signature.Params().At(i).Type(), args[i], nil, nil)
 		}
 
-		tree[pkg.PkgPath] = pkg
+		call := &DynamicCall{Func: t.transpileExpr(n.Fun), Arguments: packVariadic(n, signature, args)}
 
-		for _, imported := range pkg.Imports {
-			toVisit = append(toVisit, imported)
+		if signature.Results().Len() == 1 {
+			return t.maybeCast(call, signature.Results().At(0).Type())
 		}
+		return call
 	}
 
-	return tree
-}
-
-func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
-	var args []Node
-	for _, arg := range n.Args {
-		args = append(args, t.transpileExpr(arg))
+	// Conversions to named types (e.g. Kind(x)) are conversions to their
+	// underlying types. Conversions to unnamed types are handled as builtins.
+	if tv := t.TypesInfo.Types[n.Fun]; tv.IsType() {
+		if named, ok := t.subst(tv.Type).(*types.Named); ok {
+			return t.transpileConversion(n.Args[0], args[0], named)
+		}
 	}
 
-	callee := typeutil.Callee(t.TypesInfo, n)
+	// Byte slices are represented as strings, as they are by sprig.
+	if tv := t.TypesInfo.Types[n.Fun]; tv.IsType() && isByteSlice(tv.Type) {
+		return args[0]
+	}
 
 	// go builtins
 	if callee == nil || callee.Pkg() == nil {
@@ -885,7 +2762,7 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 		case "any":
 			return args[0]
 		case "panic":
-			return &BuiltInCall{FuncName: "fail", Arguments: args}
+			return &BuiltInCall{FuncName: "fail", Arguments: []Node{t.annotateFailure(n, args[0])}}
 		case "string":
 			x := t.typeOf(n.Args[0])
 			if x.String() == "byte" {
@@ -898,9 +2775,13 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 		case "len":
 			return t.maybeCast(&Call{FuncName: "_shims.len", Arguments: args}, types.Typ[types.Int])
 		case "delete":
-			return &BuiltInCall{FuncName: "unset", Arguments: args}
+			return &BuiltInCall{FuncName: "unset", Arguments: []Node{args[0], t.transpileMapKey(n.Args[1])}}
 		default:
-			panic(fmt.Sprintf("unsupport golang builtin %q", n.Fun.(*ast.Ident).Name))
+			panic(&Unsupported{
+				Node: n,
+				Fset: t.Fset,
+				Msg:  fmt.Sprintf("unsupported golang builtin %q", n.Fun.(*ast.Ident).Name),
+			})
 		}
 	}
 
@@ -916,7 +2797,12 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 		// In theory this could panic in the case of something like:
 		// `x := mystruct.MyMethod; x()`
 		// That's not supported any how.
-		reciever = t.transpileExpr(n.Fun.(*ast.SelectorExpr).X)
+		// Encodings (e.g. base64.StdEncoding) are package level variables of
+		// another package and can't be transpiled. They only determine what
+		// the call is lowered onto.
+		if x := n.Fun.(*ast.SelectorExpr).X; base64Encoding(t.TypesInfo, x) == "" {
+			reciever = t.transpileExpr(x)
+		}
 
 		recieverName := ""
 		switch x := recv.Type().(type) {
@@ -929,53 +2815,47 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 		id = callee.Pkg().Path() + ".(" + recieverName + ")." + callee.Name()
 	}
 
-	// Before checking anything else, search for a +gotohelm:builtin=X
-	// directive. If we find such a directive, we'll emit a BuiltInCall node
-	// with the contents of the directive. The results are cached in t.builtins
-	// as an optimization.
-	if _, ok := t.builtins[id]; !ok {
-		t.builtins[id] = ""
-		pkg := t.packages[callee.Pkg().Path()]
+	// Registered calls, which include support for commonly used standard
+	// library and third party functions, take precedence over directives and
+	// transpiled functions. See [RegisterCall] and [TranspileOptions.Calls].
+	if fn := t.callFor(id); fn != nil {
+		return fn(&CallContext{
+			Call:      n,
+			Receiver:  reciever,
+			Signature: signature,
+			Fset:      t.Fset,
+			TypesInfo: t.TypesInfo,
+			t:         t,
+		}, args)
+	}
 
-		if fnDecl := findNearest[*ast.FuncDecl](pkg, callee.Pos()); fnDecl != nil {
-			directives := parseDirectives(fnDecl.Doc.Text())
-			t.builtins[id] = directives["builtin"]
-		}
+	// Otherwise, search for a +gotohelm:builtin=X directive. If we find such
+	// a directive, we'll emit a BuiltInCall node with the contents of the
+	// directive. The results are cached in t.builtins as an optimization.
+	if _, ok := t.builtins[id]; !ok {
+		t.builtins[id] = t.builtinFor(callee.(*types.Func))
 	}
 
 	if builtin := t.builtins[id]; builtin != "" {
-		if signature.Results().Len() < 2 {
-			return &BuiltInCall{FuncName: builtin, Arguments: args}
-		}
+		return t.transpileBuiltin(n, signature, builtin, args)
+	}
 
-		// Special case, if the return signature is (T, error). We'll
-		// automagically wrap the builtin invocation with (list CALLEXPR nil)
-		// so it looks like this function returns an error similar to its go
-		// counter part. In reality, there's no error handling in templates as
-		// the template execution will be halted whenever a helper returns a
-		// non-nil error.
-		if named, ok := signature.Results().At(1).Type().(*types.Named); ok && named.Obj().Pkg() == nil && named.Obj().Name() == "error" {
-			return &BuiltInCall{
-				FuncName: "list",
-				Arguments: []Node{
-					&BuiltInCall{FuncName: builtin, Arguments: args},
-					&Literal{Value: "nil"},
-				},
+	// Call to function within the same package or a helper package. A-Okay.
+	// It's transpiled. NB: This is intentionally after the builtins check to
+	// support our bootstrap package's builtin bindings.
+	if callee.Pkg().Path() == t.Package.PkgPath || isHelperPackage(callee.Pkg()) {
+		var call Node
+
+		// Arguments passed by value are copies.
+		params := callee.(*types.Func).Origin().Type().(*types.Signature).Params()
+		for i := 0; i < params.Len() && i < len(n.Args); i++ {
+			if signature.Variadic() && i == params.Len()-1 {
+				break
 			}
+			args[i] = t.copyValue(n.Args[i], signature.Params().At(i).Type(), args[i], params.At(i), t.bodyOf(callee.(*types.Func)))
 		}
 
-		panic(&Unsupported{
-			Fset: t.Fset,
-			Node: n,
-			Msg:  fmt.Sprintf("unsupported usage of builtin directive for signature: %v", signature),
-		})
-	}
-
-	// Call to function within the same package. A-Okay. It's transpiled. NB:
-	// This is intentionally after the builtins check to support our bootstrap
-	// package's builtin bindings.
-	if callee.Pkg().Path() == t.Package.PkgPath {
-		var call Node
+		args = packVariadic(n, signature, args)
 
 		// Method call.
 		if r := callee.Type().(*types.Signature).Recv(); r != nil {
@@ -991,17 +2871,17 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 			if _, ok := typ.(*types.Named); !ok {
 				panic(&Unsupported{Fset: t.Fset, Node: n, Msg: "method calls with not pointer type with named type"})
 			}
-			var receiverArg Node
-
 			// When receiver is a pointer then dictionary can be passed as is.
-			// When receiver is not a pointer then dictionary is a deep copied.
-			receiverArg = &BuiltInCall{FuncName: "deepCopy", Arguments: []Node{t.transpileExpr(n.Fun.(*ast.SelectorExpr).X)}}
-			if mutable {
-				receiverArg = t.transpileExpr(n.Fun.(*ast.SelectorExpr).X)
+			// When receiver is not a pointer then dictionary is a deep copied,
+			// unless it's never mutated.
+			x := n.Fun.(*ast.SelectorExpr).X
+			receiverArg := t.transpileExpr(x)
+			if !mutable {
+				receiverArg = t.copyValue(x, typ, receiverArg, r, t.bodyOf(callee.(*types.Func)))
 			}
 
 			call = &Call{
-				FuncName: fmt.Sprintf("%s.%s", t.namespaceFor(callee.Pkg()), t.funcNameFor(callee.(*types.Func))),
+				FuncName: fmt.Sprintf("%s.%s", t.namespaceFor(callee.Pkg()), t.calleeNameFor(callee.(*types.Func), n.Fun)),
 				// Method calls come in as a "top level" CallExpr where .Fun is the
 				// selector up to that call. e.g. `Foo.Bar.Baz()` will be a `CallExpr`.
 				// It's `.Fun` is a `SelectorExpr` where `.X` is `Foo.Bar`, the receiver,
@@ -1009,7 +2889,7 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 				Arguments: append([]Node{receiverArg}, args...),
 			}
 		} else {
-			call = &Call{FuncName: fmt.Sprintf("%s.%s", t.namespaceFor(callee.Pkg()), t.funcNameFor(callee.(*types.Func))), Arguments: args}
+			call = &Call{FuncName: fmt.Sprintf("%s.%s", t.namespaceFor(callee.Pkg()), t.calleeNameFor(callee.(*types.Func), n.Fun)), Arguments: args}
 		}
 
 		// If there's only a single return value, we'll possibly want to wrap
@@ -1022,130 +2902,90 @@ func (t *Transpiler) transpileCallExpr(n *ast.CallExpr) Node {
 		return call
 	}
 
-	// Finally, we fall to calls to any other functions. We'll handle any
-	// special cases that require a bit of extra fiddling to make work falling
-	// back to a not supported message.
-
-	switch id {
-	case "sort.Strings":
-		return &BuiltInCall{FuncName: "sortAlpha", Arguments: args}
-	case "strings.TrimSuffix":
-		return &BuiltInCall{FuncName: "trimSuffix", Arguments: []Node{args[1], args[0]}}
-	case "strings.TrimPrefix":
-		return &BuiltInCall{FuncName: "trimPrefix", Arguments: []Node{args[1], args[0]}}
-	case "strings.ReplaceAll":
-		return &BuiltInCall{FuncName: "replace", Arguments: []Node{args[1], args[2], args[0]}}
-	case "k8s.io/apimachinery/pkg/util/intstr.FromInt32", "k8s.io/apimachinery/pkg/util/intstr.FromInt", "k8s.io/apimachinery/pkg/util/intstr.FromString":
-		return args[0]
-	case "k8s.io/utils/ptr.Deref":
-		return t.maybeCast(&Call{FuncName: "_shims.ptr_Deref", Arguments: args}, signature.Results().At(0).Type())
-	case "k8s.io/utils/ptr.To":
-		return args[0]
-	case "k8s.io/utils/ptr.Equal":
-		return &Call{FuncName: "_shims.ptr_Equal", Arguments: args}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.MustDuration":
-		return args[0]
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.Dig":
-		return &BuiltInCall{FuncName: "dig", Arguments: append(args[2:], args[1], args[0])}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.Unwrap":
-		return &Selector{Expr: args[0], Field: "AsMap"}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.UnmarshalInto":
-		return args[0]
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.Compact2", "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.Compact3":
-		return &Call{FuncName: "_shims.compact", Arguments: args}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.AsIntegral":
-		return &Call{FuncName: "_shims.asintegral", Arguments: args}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.AsNumeric":
-		return &Call{FuncName: "_shims.asnumeric", Arguments: args}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.DictTest":
-		valueType := callee.(*types.Func).Type().(*types.Signature).TypeParams().At(1)
-		return &Call{FuncName: "_shims.dicttest", Arguments: append(args, t.zeroOf(valueType))}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.Merge":
-		dict := DictLiteral{}
-		return &BuiltInCall{FuncName: "merge", Arguments: append([]Node{&dict}, args...)}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.(Values).AsMap":
-		return &Selector{Expr: reciever, Field: "AsMap"}
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.MergeTo":
-		dict := DictLiteral{}
-		return &BuiltInCall{FuncName: "merge", Arguments: append([]Node{&dict}, args...)}
-
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.Lookup":
-		// Super ugly but it's fairly safe to assume that the return type of
-		// Lookup will always be a pointer as only pointers implement
-		// kube.Object.
-		// Type params are difficult to work with so its easiest to extract the
-		// return value (Which it a generic in Lookup) of the "instance" of the
-		// function signature.
-		k8sType := signature.Results().At(0).Type().(*types.Pointer).Elem().(*types.Named).Obj()
-
-		// Step through the client set's Scheme to automatically infer the
-		// APIVersion and Kind of objects. We don't want any accidental typos
-		// or mistyping to occur.
-		for gvk, typ := range scheme.Scheme.AllKnownTypes() {
-			if typ.PkgPath() == k8sType.Pkg().Path() && typ.Name() == k8sType.Name() {
-				apiVersion, kind := gvk.ToAPIVersionAndKind()
-
-				// Inject the apiVersion and kind as arguments and snip `dot`
-				// from the arguments list.
-				args := append([]Node{NewLiteral(apiVersion), NewLiteral(kind)}, args[1:]...)
-
-				return &Call{FuncName: "_shims.lookup", Arguments: args}
-			}
-		}
-
-		// If we couldn't find the object in the scheme, panic. It's probably
-		// due to the usage of a 3rd party resource. If you hit this, just
-		// inject a Scheme into the transpiler instead of relying on the kube
-		// client's builtin scheme.
-		panic(fmt.Sprintf("unrecognized type: %v", k8sType))
-
-	case "github.com/redpanda-data/helm-charts/pkg/gotohelm/helmette.TypeTest":
-		typ := signature.Results().At(0).Type()
-		if basic, ok := typ.(*types.Basic); ok {
-			if basic.Info()&types.IsNumeric != 0 {
-				panic(&Unsupported{
-					Fset: t.Fset,
-					Node: n,
-					Msg:  "type checks on numeric types are unreliable due to JSON casting all numbers to float64's. Instead use `helmette.IsNumeric` or `helmette.AsIntegral`",
-				})
+	// Finally, any other function is unsupported. Support may be added with
+	// [RegisterCall].
+	panic(&Unsupported{
+		Node:        n,
+		Fset:        t.Fset,
+		Msg:         fmt.Sprintf("unsupported function %q", id),
+		Alternative: alternatives[id],
+	})
+}
+
+// packVariadic packs the variadic arguments of n, a call of a function with
+// the given signature, into a list, just as go would pack them into a slice.
+// Spread arguments (f(xs...)) are already slices and are returned as is.
+func packVariadic(n *ast.CallExpr, signature *types.Signature, args []Node) []Node {
+	if !signature.Variadic() || n.Ellipsis.IsValid() {
+		return args
+	}
+
+	fixed := signature.Params().Len() - 1
+
+	// Go passes a nil slice if no variadic arguments are provided.
+	if len(args) == fixed {
+		return append(args, &Nil{})
+	}
+
+	return append(args[:fixed:fixed], &BuiltInCall{FuncName: "list", Arguments: args[fixed:]})
+}
+
+// spreadElements returns the elements of e, the spread argument of a call,
+// if they're known at transpile time. That is if e is a slice literal or an
+// append of individual elements onto one.
+func (t *Transpiler) spreadElements(e ast.Expr) ([]ast.Expr, bool) {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.CompositeLit:
+		for _, el := range e.Elts {
+			if _, ok := el.(*ast.KeyValueExpr); ok {
+				return nil, false
 			}
 		}
-		return &Call{FuncName: "_shims.typetest", Arguments: []Node{t.transpileTypeRepr(typ), args[0], t.zeroOf(typ)}}
-
-	// Support for resource.Quantity. In helm world, resource.Quantity is
-	// always represented as it's JSON representation of either a string or a
-	// number with polyfills in the bootstrap package for the following
-	// methods.
-	// WARNING: There is not 100% compatibility and this is on purpose.
-	case "k8s.io/apimachinery/pkg/api/resource.MustParse":
-		return &Call{FuncName: "_shims.resource_MustParse", Arguments: args}
-	case "k8s.io/apimachinery/pkg/api/resource.(*Quantity).Value":
-		return &Cast{To: "int64", X: &Call{FuncName: "_shims.resource_Value", Arguments: append([]Node{reciever}, args...)}}
-	case "k8s.io/apimachinery/pkg/api/resource.(*Quantity).MilliValue":
-		return &Cast{To: "int64", X: &Call{FuncName: "_shims.resource_MilliValue", Arguments: append([]Node{reciever}, args...)}}
-	case "k8s.io/apimachinery/pkg/api/resource.(*Quantity).String":
-		// Similarly to DeepCopy, we're exploit the JSON representation of
-		// resource.Quantity here and rely on MustParse to simply normalize the
-		// representation.
-		return &Call{FuncName: "_shims.resource_MustParse", Arguments: []Node{reciever}}
-	case "k8s.io/apimachinery/pkg/api/resource.(Quantity).DeepCopy":
-		// DeepCopy is supported in a bit of a hacky way. We call "MustParse"
-		// which takes the JSON (string) representation of a resource.Quantity
-		// and parses it returning a new resource.Quantity, which is
-		// functionally equivalent. It has the added benefit of normalizing the
-		// string form of the Quantity.
-		return &Call{FuncName: "_shims.resource_MustParse", Arguments: []Node{reciever}}
+		return e.Elts, true
 
-	default:
-		panic(fmt.Sprintf("unsupported function %q", id))
+	case *ast.CallExpr:
+		ident, ok := ast.Unparen(e.Fun).(*ast.Ident)
+		if !ok || e.Ellipsis.IsValid() {
+			return nil, false
+		}
+		if builtin, ok := t.TypesInfo.Uses[ident].(*types.Builtin); !ok || builtin.Name() != "append" {
+			return nil, false
+		}
+
+		elements, ok := t.spreadElements(e.Args[0])
+		if !ok {
+			return nil, false
+		}
+		n := len(elements)
+		return append(elements[:n:n], e.Args[1:]...), true
 	}
+
+	return nil, false
+}
+
+// transpileTypeTest returns a [Node] equivalent to `x.(typ)` when used as a
+// multi-value expression (`_, ok := x.(typ)`) by way of `_shims.typetest`.
+func (t *Transpiler) transpileTypeTest(n ast.Node, typ types.Type, x Node) Node {
+	typ = t.subst(typ)
+
+	if basic, ok := typ.(*types.Basic); ok {
+		if basic.Info()&types.IsNumeric != 0 {
+			panic(&Unsupported{
+				Fset:        t.Fset,
+				Node:        n,
+				Msg:         "type checks on numeric types are unreliable due to JSON casting all numbers to float64's",
+				Alternative: "helmette.AsNumeric or helmette.AsIntegral",
+			})
+		}
+	}
+	return &Call{FuncName: "_shims.typetest", Arguments: []Node{t.transpileTypeRepr(typ), x, t.zeroOf(typ)}}
 }
 
 func (t *Transpiler) transpileTypeRepr(typ types.Type) Node {
 	// NB: Ideally, we'd just use typ.String(). Sadly, we can't as typ.String()
 	// will return `any` but we need to match the result of fmt.Sprintf("%T")
 	// which returns `interface {}`.
-	switch typ := typ.(type) {
+	switch typ := t.subst(typ).(type) {
 	case *types.Pointer:
 		return &BuiltInCall{FuncName: "printf", Arguments: []Node{
 			NewLiteral("*%s"),
@@ -1199,18 +3039,27 @@ func (t *Transpiler) transpileConst(c *types.Const) Node {
 	return t.maybeCast(&Literal{Value: strconv.FormatFloat(as64, 'f', -1, 64)}, c.Type())
 }
 
+// tmpVar returns an [Ident] with a unique name, suitable for use as a
+// temporary variable.
+func (t *Transpiler) tmpVar(prefix string) *Ident {
+	t.tmpCount++
+	return &Ident{Name: fmt.Sprintf("tmp_%s_%d", prefix, t.tmpCount)}
+}
+
 func (t *Transpiler) isString(e ast.Expr) bool {
 	return types.AssignableTo(t.TypesInfo.TypeOf(e), types.Typ[types.String])
 }
 
 func (t *Transpiler) typeOf(expr ast.Expr) types.Type {
-	return t.TypesInfo.TypeOf(expr)
+	return t.subst(t.TypesInfo.TypeOf(expr))
 }
 
 func (t *Transpiler) zeroOf(typ types.Type) Node {
 	// TODO need to detect and reject or special case implementors of
 	// json.Marshaler. Getting a handle to a that interface is... difficult.
 
+	typ = t.subst(typ)
+
 	// Special cases.
 	switch typ.String() {
 	case "k8s.io/apimachinery/pkg/apis/meta/v1.Time":
@@ -1239,6 +3088,13 @@ func (t *Transpiler) zeroOf(typ types.Type) Node {
 	case *types.Pointer, *types.Map, *types.Interface, *types.Slice:
 		return &Nil{}
 
+	case *types.Array:
+		var elts []Node
+		for i := int64(0); i < underlying.Len(); i++ {
+			elts = append(elts, t.zeroOf(underlying.Elem()))
+		}
+		return &BuiltInCall{FuncName: "list", Arguments: elts}
+
 	case *types.Struct:
 		var out DictLiteral
 
@@ -1253,7 +3109,7 @@ func (t *Transpiler) zeroOf(typ types.Type) Node {
 			}
 
 			out.KeysValues = append(out.KeysValues, &KeyValue{
-				Key:   strconv.Quote(field.JSONName()),
+				Key:   NewLiteral(field.JSONName()),
 				Value: t.zeroOf(field.Field.Type()),
 			})
 		}
@@ -1267,36 +3123,30 @@ func (t *Transpiler) zeroOf(typ types.Type) Node {
 // getFields returns a _flattened_ list (embedded structs) of structFields for
 // the given struct type.
 func (t *Transpiler) getFields(root *types.Struct) []structField {
-	_, rootSpec := t.getStructType(root)
-
-	// Would be nice to have a tuple type but it's a bit too verbose for my
-	// test.
 	typs := []*types.Struct{root}
-	specs := []*ast.StructType{rootSpec}
 
 	var fields []structField
-	for len(typs) > 0 && len(specs) > 0 {
+	for len(typs) > 0 {
 		s := typs[0]
-		spec := specs[0]
-
 		typs = typs[1:]
-		specs = specs[1:]
 
-		for i, astField := range spec.Fields.List {
+		definitions := t.getFieldDefinitions(s)
+
+		for i := 0; i < s.NumFields(); i++ {
 			field := structField{
-				Field:      s.Field(i),
-				Tag:        parseTag(s.Tag(i)),
-				Definition: astField,
+				Field: s.Field(i),
+				Tag:   parseTag(s.Tag(i)),
+			}
+
+			if definitions != nil {
+				field.Definition = definitions[i]
 			}
 
 			// If we encounter a JSON inlined field (See JSONInline for
 			// details), merge the embedded struct into our list of fields to
 			// support direct access thereof, just list go.
 			if field.JSONInline() {
-				embeddedType := field.Field.Type().(*types.Named).Underlying().(*types.Struct)
-				_, embeddedSpec := t.getStructType(embeddedType)
-				typs = append(typs, embeddedType)
-				specs = append(specs, embeddedSpec)
+				typs = append(typs, field.Field.Type().(*types.Named).Underlying().(*types.Struct))
 			}
 
 			fields = append(fields, field)
@@ -1306,6 +3156,30 @@ func (t *Transpiler) getFields(root *types.Struct) []structField {
 	return fields
 }
 
+// getFieldDefinitions returns the [ast.Field] of each field in the given
+// struct type, indexed the same as [types.Struct.Field]. It returns nil if
+// the syntax of the struct's defining package is not available.
+func (t *Transpiler) getFieldDefinitions(typ *types.Struct) []*ast.Field {
+	if typ.NumFields() == 0 {
+		return nil
+	}
+
+	_, spec := t.getStructType(typ)
+	if spec == nil {
+		return nil
+	}
+
+	var definitions []*ast.Field
+	for _, field := range spec.Fields.List {
+		// Embedded fields have no names but still define a single field.
+		for i := 0; i < max(len(field.Names), 1); i++ {
+			definitions = append(definitions, field)
+		}
+	}
+
+	return definitions
+}
+
 // maybeCast may wrap the provided [Node] with a [Cast] to the provided
 // [types.Type] if it's possible that text/template or sprig would misinterpret
 // the value.
@@ -1314,7 +3188,7 @@ func (t *Transpiler) getFields(root *types.Struct) []structField {
 func (t *Transpiler) maybeCast(n Node, to types.Type) Node {
 	// TODO: This can probably be optimized to not cast as frequently but
 	// should otherwise perform just fine.
-	if basic, ok := to.(*types.Basic); ok {
+	if basic, ok := t.subst(to).Underlying().(*types.Basic); ok {
 		switch basic.Kind() {
 		case types.Int, types.Int32, types.UntypedInt:
 			return &Cast{X: n, To: "int"}
@@ -1336,15 +3210,17 @@ func (t *Transpiler) maybeCast(n Node, to types.Type) Node {
 }
 
 // getTypeSpec returns the [ast.StructType] for the given named type and the
-// [packages.Package] that contains the definition.
+// [packages.Package] that contains the definition. If the syntax of the
+// defining package is not available, as is the case when running as an
+// [Analyzer], both return values will be nil.
 func (t *Transpiler) getStructType(typ *types.Struct) (*packages.Package, *ast.StructType) {
 	if typ.NumFields() == 0 {
 		panic("unhandled")
 	}
 
-	pack := t.Package.Imports[typ.Field(0).Pkg().Path()]
-	if pack == nil {
-		pack = t.Package
+	pack := t.packages[typ.Field(0).Pkg().Path()]
+	if pack == nil || len(pack.Syntax) == 0 {
+		return nil, nil
 	}
 
 	// This is quite strange, struct
@@ -1365,8 +3241,15 @@ func (t *Transpiler) namespaceFor(pkg *types.Package) string {
 		return ns
 	}
 
+	// When running as an [Analyzer], the syntax of dependencies isn't
+	// available. The namespace is unimportant in such cases.
+	var syntax []*ast.File
+	if p, ok := t.packages[pkg.Path()]; ok {
+		syntax = p.Syntax
+	}
+
 	var namespace *string
-	for _, f := range t.packages[pkg.Path()].Syntax {
+	for _, f := range syntax {
 		directives := parseDirectives(f.Doc.Text())
 
 		ns, ok := directives["namespace"]
@@ -1386,6 +3269,153 @@ func (t *Transpiler) namespaceFor(pkg *types.Package) string {
 	return t.namespaces[pkg]
 }
 
+// callFor returns the [CallFunc] to transpile calls of the function or
+// method id with, if any. [TranspileOptions.Calls] take precedence over
+// registered calls.
+func (t *Transpiler) callFor(id string) CallFunc {
+	if fn, ok := t.calls[id]; ok {
+		return fn
+	}
+	return calls[id]
+}
+
+// transpileBuiltin transpiles n, a call of a function with the given
+// signature and transpiled arguments, into a call of the template builtin.
+func (t *Transpiler) transpileBuiltin(n *ast.CallExpr, signature *types.Signature, builtin string, args []Node) Node {
+	// Templates have no way of spreading arguments, so spreads (f(xs...))
+	// are only supported if the elements of xs are known or if f is printf,
+	// which _shims.fmt_Sprintf spreads by the length of xs.
+	if n.Ellipsis.IsValid() {
+		elements, ok := t.spreadElements(n.Args[len(n.Args)-1])
+		if !ok && builtin == "printf" && len(args) == 2 {
+			spread := &BuiltInCall{FuncName: "default", Arguments: []Node{&BuiltInCall{FuncName: "list"}, args[1]}}
+			return &Call{FuncName: "_shims.fmt_Sprintf", Arguments: []Node{args[0], spread}}
+		}
+		if !ok {
+			panic(&Unsupported{
+				Fset: t.Fset,
+				Node: n,
+				Msg:  fmt.Sprintf("spread arguments of builtins must be slice literals or appends onto them: %v", signature),
+			})
+		}
+
+		fixed := len(args) - 1
+		args = args[:fixed:fixed]
+		for _, el := range elements {
+			args = append(args, t.transpileExpr(el))
+		}
+	}
+
+	if signature.Results().Len() < 2 {
+		return &BuiltInCall{FuncName: builtin, Arguments: args}
+	}
+
+	// Special case, if the return signature is (T, error). We'll
+	// automagically wrap the builtin invocation with (list CALLEXPR nil)
+	// so it looks like this function returns an error similar to its go
+	// counter part. In reality, there's no error handling in templates as
+	// the template execution will be halted whenever a helper returns a
+	// non-nil error.
+	if named, ok := signature.Results().At(1).Type().(*types.Named); ok && named.Obj().Pkg() == nil && named.Obj().Name() == "error" {
+		return &BuiltInCall{
+			FuncName: "list",
+			Arguments: []Node{
+				&BuiltInCall{FuncName: builtin, Arguments: args},
+				&Literal{Value: "nil"},
+			},
+		}
+	}
+
+	panic(&Unsupported{
+		Fset: t.Fset,
+		Node: n,
+		Msg:  fmt.Sprintf("unsupported usage of builtin directive for signature: %v", signature),
+	})
+}
+
+// builtinFor returns the value of the +gotohelm:builtin directive of the given
+// function, if any.
+func (t *Transpiler) builtinFor(fn *types.Func) string {
+	if pkg, ok := t.packages[fn.Pkg().Path()]; ok && len(pkg.Syntax) > 0 {
+		if fnDecl := findNearest[*ast.FuncDecl](pkg, fn.Pos()); fnDecl != nil {
+			return parseDirectives(fnDecl.Doc.Text())["builtin"]
+		}
+		return ""
+	}
+
+	// When running as an [Analyzer], the syntax of dependencies isn't
+	// available. Their directives are instead propagated as facts.
+	var fact builtinFact
+	if t.pass != nil && t.pass.ImportObjectFact(fn.Origin(), &fact) {
+		return fact.Builtin
+	}
+
+	return ""
+}
+
+// annotateFailure appends the [helmette.FailureSource] of the call n to msg,
+// if failures are to be annotated. The function name and file mimic those
+// reported by the go runtime, so go code may produce the same message via
+// [helmette.AnnotateFailure].
+func (t *Transpiler) annotateFailure(n *ast.CallExpr, msg Node) Node {
+	if !t.annotateFailures || !n.Pos().IsValid() {
+		return msg
+	}
+
+	// The runtime names of function literals depend on inlining and can't be
+	// determined ahead of time. Failures within them are not annotated, as
+	// is the case for [helmette.AnnotateFailure].
+	if lit := findNearest[*ast.FuncLit](t.Package, n.Pos()); lit != nil {
+		return msg
+	}
+
+	decl := findNearest[*ast.FuncDecl](t.Package, n.Pos())
+	if decl == nil {
+		return msg
+	}
+
+	fn := t.TypesInfo.ObjectOf(decl.Name).(*types.Func)
+
+	// The runtime formats methods as pkg.Type.Method or pkg.(*Type).Method
+	// and elides type arguments, e.g. pkg.Fn[...] or pkg.Type[...].Method.
+	typeName := func(named *types.Named) string {
+		if named.TypeParams().Len() > 0 || named.TypeArgs().Len() > 0 {
+			return named.Obj().Name() + "[...]"
+		}
+		return named.Obj().Name()
+	}
+
+	name := fn.Name()
+	if fn.Type().(*types.Signature).TypeParams().Len() > 0 {
+		name += "[...]"
+	}
+
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		switch rtyp := recv.Type().(type) {
+		case *types.Named:
+			name = fmt.Sprintf("%s.%s", typeName(rtyp), name)
+		case *types.Pointer:
+			name = fmt.Sprintf("(*%s).%s", typeName(rtyp.Elem().(*types.Named)), name)
+		}
+	}
+
+	pos := t.Fset.PositionFor(n.Pos(), true)
+	pkgPath := fn.Pkg().Path()
+
+	source := helmette.FailureSource(
+		pkgPath+"."+name,
+		path.Join(pkgPath, filepath.Base(pos.Filename)),
+		pos.Line,
+	)
+
+	// print is used rather than printf to avoid needing to escape any
+	// formatting directives within source.
+	return &BuiltInCall{
+		FuncName:  "print",
+		Arguments: []Node{msg, &Literal{Value: strconv.Quote(source)}},
+	}
+}
+
 // funcNameFor returns the transpiled "function" name for a given function
 // taking into account directives and receivers, if any.
 func (t *Transpiler) funcNameFor(fn *types.Func) string {
@@ -1395,9 +3425,13 @@ func (t *Transpiler) funcNameFor(fn *types.Func) string {
 
 	// TODO should probably make a directives cache if this ever gets to be too
 	// slow.
-	decl := findNearest[*ast.FuncDecl](t.packages[fn.Pkg().Path()], fn.Pos())
-
-	directives := parseDirectives(decl.Doc.Text())
+	directives := map[string]string{}
+	if pkg, ok := t.packages[fn.Pkg().Path()]; ok && len(pkg.Syntax) > 0 {
+		// Methods of interfaces have no declaration of their own.
+		if decl := findNearest[*ast.FuncDecl](pkg, fn.Pos()); decl != nil {
+			directives = parseDirectives(decl.Doc.Text())
+		}
+	}
 
 	fnName := fn.Name()
 	if name, ok := directives["name"]; ok {
@@ -1480,6 +3514,9 @@ func (f *structField) JSONName() string {
 // KubernetesOptional returns true if this field's comment contains any of
 // Kubernetes' optional annotations.
 func (f *structField) KubernetesOptional() bool {
+	if f.Definition == nil {
+		return false
+	}
 	optional, _ := regexp.MatchString(`\+optional`, f.Definition.Doc.Text())
 	return optional
 }
@@ -57,6 +57,8 @@ type TestSpec struct {
 	// .Values has not be mutated by the chart. Set to `true` to disable.
 	ValuesChanged bool
 	Values        []map[string]any
+	// Options are the [TranspileOptions] to transpile the chart with.
+	Options TranspileOptions
 }
 
 var testSpecs = map[string]TestSpec{
@@ -83,9 +85,71 @@ var testSpecs = map[string]TestSpec{
 			{"numeric": 1.5},
 			{"numeric": true},
 			{"numeric": ""},
+			{"base": 16},
+			{"base": 0},
+			{"base": 37},
 		},
 	},
 	"syntax": {},
+	"closures": {
+		Values: []map[string]any{
+			{},
+			{"threshold": 3},
+		},
+	},
+	"calls": {
+		Options: TranspileOptions{Calls: map[string]CallFunc{
+			"path.Base": func(ctx *CallContext, args []Node) Node {
+				return &BuiltInCall{FuncName: "base", Arguments: args}
+			},
+			"path.Dir": func(ctx *CallContext, args []Node) Node {
+				return &BuiltInCall{FuncName: "dir", Arguments: args}
+			},
+			"path.Ext": func(ctx *CallContext, args []Node) Node {
+				return &BuiltInCall{FuncName: "ext", Arguments: args}
+			},
+			"path.Join": func(ctx *CallContext, args []Node) Node {
+				joined := &BuiltInCall{FuncName: "join", Arguments: []Node{NewLiteral("/"), &BuiltInCall{FuncName: "list", Arguments: args}}}
+				return &BuiltInCall{FuncName: "clean", Arguments: []Node{joined}}
+			},
+			"strings.HasPrefix": func(ctx *CallContext, args []Node) Node {
+				return &BuiltInCall{FuncName: "regexMatch", Arguments: []Node{
+					&BuiltInCall{FuncName: "printf", Arguments: []Node{NewLiteral("^%s"), &BuiltInCall{FuncName: "regexQuoteMeta", Arguments: []Node{args[1]}}}},
+					args[0],
+				}}
+			},
+		}},
+		Values: []map[string]any{
+			{"file": "/etc/redpanda/redpanda.yaml"},
+			{"file": "redpanda"},
+			{},
+		},
+	},
+	"failures": {
+		Options: TranspileOptions{AnnotateFailures: true},
+		Values: []map[string]any{
+			{"mode": "none"},
+			{"mode": "panic"},
+			{"mode": "fail"},
+			{"mode": "required"},
+			{"mode": "required", "value": "present"},
+			{"mode": "method"},
+			{"mode": "value-method"},
+			{"mode": "closure"},
+			{"mode": "generic"},
+			{"mode": "generic-method"},
+		},
+	},
+	"imports": {
+		Values: []map[string]any{
+			{},
+			{"fullnameOverride": "override"},
+		},
+	},
+	"unsupported": {
+		// unsupported is exercised by TestDiagnostics.
+		Unsupported: true,
+	},
 	"labels": {
 		Values: []map[string]any{
 			{"commonLabels": map[string]any{"test": "test"}},
@@ -122,6 +186,14 @@ var testSpecs = map[string]TestSpec{
 			{"t": float64(1)},
 			{"t": true},
 			{"t": "a string"},
+			{"t": false},
+			{"t": []any{1, 2}},
+			{"t": map[string]any{"a": 1}},
+			{},
+			{"method": "none"},
+			{"method": "sasl"},
+			{"object": map[string]any{"Key": "a"}},
+			{"object": map[string]any{"Key": "a", "with_tag": 0}},
 		},
 	},
 }
@@ -163,7 +235,7 @@ func TestTranspile(t *testing.T) {
 				t.Skipf("%q is not currently supported", pkg.Name)
 			}
 
-			chart, err := Transpile(pkg)
+			chart, err := TranspileWithOptions(pkg, spec.Options)
 			require.NoError(t, err)
 
 			for _, f := range chart.Files {
@@ -212,11 +284,20 @@ func TestTranspile(t *testing.T) {
 					clonedDot, err := valuesutil.RoundTripThrough[map[string]any](dot)
 					require.NoError(t, err)
 
-					actualJSON, err := helmRunner.Render(ctx, &dot)
-					require.NoError(t, err, "error from helm runner")
+					actualJSON, helmErr := helmRunner.Render(ctx, &dot)
+					gocodeJSON, goErr := goRunner.Render(ctx, &dot)
+
+					// If the go code fails, helm must fail with the same
+					// message. text/template prefixes the errors of
+					// functions with their position and name.
+					if goErr != nil {
+						require.Error(t, helmErr, "go code failed but helm did not: %s", goErr)
+						msg := strings.TrimPrefix(goErr.Error(), "error from go code: ")
+						require.True(t, strings.HasSuffix(helmErr.Error(), ": "+msg), "Divergence between Go code and generated template failures\nGo:   %q\nHelm: %q", msg, helmErr.Error())
+						return
+					}
 
-					gocodeJSON, err := goRunner.Render(ctx, &dot)
-					require.NoError(t, err, "error from go runner")
+					require.NoError(t, helmErr, "error from helm runner")
 
 					if spec.ValuesChanged {
 						require.NotEqual(t, clonedDot, dot)
@@ -240,6 +321,48 @@ func TestTranspile(t *testing.T) {
 	}
 }
 
+func TestDiagnostics(t *testing.T) {
+	td, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	root := filepath.Join(td, "src", "example")
+
+	pkgs, err := LoadPackages(&packages.Config{
+		Dir: root,
+		Env: append(
+			os.Environ(),
+			"GOPATH="+td,
+			"GO111MODULE=on",
+		),
+	}, "./unsupported")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+
+	// Sanity check that the default mode returns the first encountered
+	// Unsupported.
+	_, err = Transpile(pkgs[0])
+	var unsupported *Unsupported
+	require.ErrorAs(t, err, &unsupported)
+
+	_, err = TranspileWithOptions(pkgs[0], TranspileOptions{Diagnose: true})
+	var diagnostics Diagnostics
+	require.ErrorAs(t, err, &diagnostics)
+
+	var actual bytes.Buffer
+	for _, d := range diagnostics {
+		pos := d.Position()
+		pos.Filename, err = filepath.Rel(root, pos.Filename)
+		require.NoError(t, err)
+
+		fmt.Fprintf(&actual, "%s: %s (%s)\n", pos, d.Msg, d.Kind())
+		if d.Alternative != "" {
+			fmt.Fprintf(&actual, "\tconsider using %s instead\n", d.Alternative)
+		}
+	}
+
+	testutil.AssertGolden(t, testutil.Text, filepath.Join(root, "unsupported", "diagnostics.txt"), actual.Bytes())
+}
+
 type HelmRunner struct {
 	tpl    *template.Template
 	logf   func(string, ...any)
@@ -261,6 +384,7 @@ func NewHelmRunner(chartName, chartDir string, cfg *kube.RESTConfig, logf func(s
 	funcs := sprig.FuncMap()
 	funcs["include"] = runner.includeFn
 	funcs["lookup"] = runner.lookupFn
+	funcs["required"] = requiredFn
 	funcs["toYaml"] = helmette.ToYaml
 	funcs["tpl"] = runner.tplFn
 
@@ -289,6 +413,12 @@ func (r *HelmRunner) Render(ctx context.Context, dot *helmette.Dot) (map[string]
 			continue
 		}
 
+		// Following helm's conventions, templates defined in files prefixed
+		// with an _ (e.g. _shims.tpl or helper packages) are not entrypoints.
+		if tpl.Tree != nil && strings.HasPrefix(tpl.Tree.ParseName, "_") {
+			continue
+		}
+
 		r.logf("rendering %q...", spl[1])
 
 		var b bytes.Buffer
@@ -348,6 +478,17 @@ func (r *HelmRunner) lookupFn(apiVersion, kind, namespace, name string) (map[str
 	return valuesutil.UnmarshalInto[map[string]any](obj)
 }
 
+// requiredFn is an implementation of helm's `required`.
+// See https://github.com/helm/helm/blob/15f76cf83c670a329b62c2b5ddeb0864ec99daec/pkg/engine/engine.go#L154
+func requiredFn(warn string, val any) (any, error) {
+	if val == nil {
+		return val, errors.New(warn)
+	} else if s, ok := val.(string); ok && s == "" {
+		return val, errors.New(warn)
+	}
+	return val, nil
+}
+
 // tplFn is a poorman's implement of `tpl`.
 // See https://github.com/helm/helm/blob/15f76cf83c670a329b62c2b5ddeb0864ec99daec/pkg/engine/engine.go#L148
 func (r *HelmRunner) tplFn(template string, context any) (string, error) {